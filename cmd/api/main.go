@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"log/slog"
 	"meli-product-api/internal/application/service"
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/port"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
 	"meli-product-api/internal/infrastructure/adapter/http/handler"
 	jsonRepo "meli-product-api/internal/infrastructure/adapter/repository/json"
+	"meli-product-api/internal/infrastructure/analytics"
 	"meli-product-api/internal/infrastructure/config"
+	"meli-product-api/internal/infrastructure/resilience"
 	"meli-product-api/internal/infrastructure/router"
+	"meli-product-api/internal/infrastructure/search"
 	"net/http"
 	"os"
 	"os/signal"
@@ -34,7 +41,18 @@ func main() {
 	// Initialize repositories
 	logger.Info("Initializing repositories...")
 
-	productRepo, err := jsonRepo.NewProductRepository(cfg.Database.ProductsFile)
+	scorer := search.NewScorer(map[search.Field]float64{
+		search.FieldTitle:       cfg.Search.TitleBoost,
+		search.FieldBrand:       cfg.Search.BrandBoost,
+		search.FieldModel:       cfg.Search.ModelBoost,
+		search.FieldCategory:    cfg.Search.CategoryBoost,
+		search.FieldDescription: cfg.Search.DescriptionBoost,
+		search.FieldAttributes:  cfg.Search.AttributesBoost,
+	})
+
+	analyzer := analysis.NewAnalyzer()
+
+	productRepo, err := jsonRepo.NewProductRepository(cfg.Database.ProductsFile, analyzer, scorer)
 	if err != nil {
 		logger.Error("Failed to initialize product repository", "error", err)
 		log.Fatalf("Failed to initialize product repository: %v", err)
@@ -58,35 +76,191 @@ func main() {
 		log.Fatalf("Failed to initialize question repository: %v", err)
 	}
 
+	savedSearchRepo, err := jsonRepo.NewSavedSearchRepository(cfg.Database.SavedSearchesFile)
+	if err != nil {
+		logger.Error("Failed to initialize saved search repository", "error", err)
+		log.Fatalf("Failed to initialize saved search repository: %v", err)
+	}
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if cfg.Database.ProductsReloadInterval > 0 {
+		go watchProducts(jobsCtx, productRepo, cfg.Database.ProductsReloadInterval, logger)
+	}
+
+	var synonymSource port.SynonymSource
+	if cfg.Search.SynonymsFile != "" {
+		synonymRepo, err := jsonRepo.NewSynonymRepository(cfg.Search.SynonymsFile, analyzer)
+		if err != nil {
+			logger.Error("Failed to initialize synonym repository", "error", err)
+			log.Fatalf("Failed to initialize synonym repository: %v", err)
+		}
+		logger.Info("Synonyms loaded", "file", cfg.Search.SynonymsFile, "keys", synonymRepo.Len())
+
+		if cfg.Search.SynonymsReloadInterval > 0 {
+			go watchSynonyms(jobsCtx, synonymRepo, cfg.Search.SynonymsReloadInterval, logger)
+		}
+		synonymSource = synonymRepo
+	}
+
+	analyticsStore, err := analytics.NewStore(cfg.Analytics.File, cfg.Analytics.Retention)
+	if err != nil {
+		logger.Error("Failed to initialize search analytics", "error", err)
+		log.Fatalf("Failed to initialize search analytics: %v", err)
+	}
+	if cfg.Analytics.FlushInterval > 0 {
+		go flushAnalytics(jobsCtx, analyticsStore, cfg.Analytics.FlushInterval, logger)
+	}
+
 	logger.Info("✓ Repositories initialized successfully")
 
+	// Downstream "microservices" go through circuit breakers; failed calls
+	// are retried around them, so an open circuit is not retried
+	breakerSettings := resilience.BreakerSettings{
+		Window:           cfg.Breaker.Window,
+		MinRequests:      cfg.Breaker.MinRequests,
+		FailureRate:      cfg.Breaker.FailureRate,
+		OpenTimeout:      cfg.Breaker.OpenTimeout,
+		HalfOpenRequests: cfg.Breaker.HalfOpenRequests,
+	}
+	sellerBreaker := resilience.NewCircuitBreaker("seller", breakerSettings, logger)
+	reviewBreaker := resilience.NewCircuitBreaker("review", breakerSettings, logger)
+	questionBreaker := resilience.NewCircuitBreaker("question", breakerSettings, logger)
+
+	retryPolicy := resilience.RetryPolicy{
+		MaxAttempts:    cfg.Retry.MaxAttempts,
+		InitialBackoff: cfg.Retry.InitialBackoff,
+		MaxBackoff:     cfg.Retry.MaxBackoff,
+		Multiplier:     cfg.Retry.Multiplier,
+		Jitter:         cfg.Retry.Jitter,
+		Budget:         cfg.Retry.Budget,
+	}
+
+	sellerClient := resilience.NewRetrySellerClient(
+		resilience.NewBreakerSellerClient(sellerRepo, sellerBreaker),
+		resilience.NewRetrier("seller", retryPolicy, logger),
+	)
+	reviewClient := resilience.NewRetryReviewClient(
+		resilience.NewBreakerReviewClient(reviewRepo, reviewBreaker),
+		resilience.NewRetrier("review", retryPolicy, logger),
+	)
+	questionClient := resilience.NewRetryQuestionClient(
+		resilience.NewBreakerQuestionClient(questionRepo, questionBreaker),
+		resilience.NewRetrier("question", retryPolicy, logger),
+	)
+
 	// Initialize services
 	logger.Info("Initializing services...")
 
 	aggregatorService := service.NewProductAggregatorService(
 		productRepo,
-		sellerRepo,
-		reviewRepo,
-		questionRepo,
+		sellerClient,
+		reviewClient,
+		questionClient,
+		service.AggregatorOptions{
+			SellerTimeout:    cfg.Aggregator.SellerTimeout,
+			ReviewsTimeout:   cfg.Aggregator.ReviewsTimeout,
+			QuestionsTimeout: cfg.Aggregator.QuestionsTimeout,
+			RelatedTimeout:   cfg.Aggregator.RelatedTimeout,
+		},
 		logger,
 	)
 
 	searchService := service.NewProductSearchService(
 		productRepo,
+		productRepo,
+		synonymSource,
+		productRepo,
+		analyticsStore,
+		analyzer,
+		service.SearchOptions{
+			FuzzyMinResults:  cfg.Search.FuzzyMinResults,
+			AutoCorrect:      cfg.Search.AutoCorrect,
+			HistogramBuckets: cfg.Search.HistogramBuckets,
+		},
+		logger,
+	)
+
+	suggestService := service.NewProductSuggestService(
+		productRepo,
+		logger,
+	)
+
+	sellerService := service.NewSellerService(
+		sellerClient,
+		productRepo,
+		logger,
+	)
+
+	savedSearchService := service.NewSavedSearchService(
+		savedSearchRepo,
+		savedSearchRepo,
+		productRepo,
+		productRepo,
+		searchService,
+		logger,
+	)
+	productRepo.OnChange(savedSearchService.ProductsChanged)
+	go savedSearchService.Run(jobsCtx)
+
+	analyticsService := service.NewSearchAnalyticsService(
+		analyticsStore,
 		logger,
 	)
 
 	logger.Info("✓ Services initialized successfully")
 
 	// Initialize handlers
+	cursorSecret := []byte(cfg.Search.CursorSecret)
+	if len(cursorSecret) == 0 {
+		logger.Warn("SEARCH_CURSOR_SECRET not set, using a random key; pagination cursors will not survive a restart")
+		cursorSecret = make([]byte, 32)
+		if _, err := rand.Read(cursorSecret); err != nil {
+			log.Fatalf("Failed to generate cursor secret: %v", err)
+		}
+	}
+
 	productHandler := handler.NewProductHandler(
 		aggregatorService,
 		searchService,
+		suggestService,
+		handler.NewCursorCodec(cursorSecret),
+		dto.HighlightMarkers{
+			Pre:  cfg.Search.HighlightPreTag,
+			Post: cfg.Search.HighlightPostTag,
+		},
+		logger,
+	)
+
+	sellerHandler := handler.NewSellerHandler(
+		sellerService,
+		logger,
+	)
+
+	breakerHandler := handler.NewCircuitBreakerHandler(
+		[]*resilience.CircuitBreaker{sellerBreaker, reviewBreaker, questionBreaker},
+		logger,
+	)
+
+	analyticsHandler := handler.NewSearchAnalyticsHandler(
+		analyticsService,
+		cfg.Analytics.Retention,
+		logger,
+	)
+
+	savedSearchHandler := handler.NewSavedSearchHandler(
+		savedSearchService,
 		logger,
 	)
 
+	if cfg.Admin.Token == "" {
+		logger.Warn("ADMIN_TOKEN not set, admin endpoints are disabled")
+	}
+
 	// Setup router
-	r := router.NewRouter(productHandler, logger)
+	r := router.NewRouter(productHandler, sellerHandler, analyticsHandler, breakerHandler, savedSearchHandler, cfg.Admin.Token, logger)
 
 	// HTTP Server configuration
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -119,6 +293,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -128,9 +303,78 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Flush after in-flight searches finished recording
+	if err := analyticsStore.Save(); err != nil {
+		logger.Error("Failed to save search analytics", "error", err)
+	}
+
 	logger.Info("Server exited gracefully")
 }
 
+// watchSynonyms relee el archivo de sinónimos cuando cambia, sin reiniciar
+// el servidor.
+func watchSynonyms(ctx context.Context, repo *jsonRepo.SynonymRepository, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := repo.Reload()
+			if err != nil {
+				logger.Error("Failed to reload synonyms, keeping previous version", "error", err)
+				continue
+			}
+			if changed {
+				logger.Info("Synonyms reloaded", "keys", repo.Len())
+			}
+		}
+	}
+}
+
+// watchProducts relee el archivo de productos cuando cambia; las altas y
+// modificaciones disparan la evaluación de búsquedas guardadas.
+func watchProducts(ctx context.Context, repo *jsonRepo.ProductRepository, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := repo.Reload()
+			if err != nil {
+				logger.Error("Failed to reload products, keeping previous catalog", "error", err)
+				continue
+			}
+			if changed {
+				logger.Info("Products reloaded")
+			}
+		}
+	}
+}
+
+// flushAnalytics persiste la analítica de búsqueda periódicamente para no
+// perder más de un intervalo si el proceso muere sin apagarse.
+func flushAnalytics(ctx context.Context, store *analytics.Store, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := store.Save(); err != nil {
+				logger.Error("Failed to save search analytics", "error", err)
+			}
+		}
+	}
+}
+
 func setupLogger(cfg config.LoggerConfig) *slog.Logger {
 	var level slog.Level
 	switch cfg.Level {
@@ -12,11 +12,22 @@ import (
 
 var ErrProductNotFound = errors.New("product not found")
 
+// AggregatorOptions fija cuánto puede tardar cada sección del detalle,
+// contado desde que empieza la agregación y nunca más allá del deadline
+// del request. 0 deja solo el del request.
+type AggregatorOptions struct {
+	SellerTimeout    time.Duration
+	ReviewsTimeout   time.Duration
+	QuestionsTimeout time.Duration
+	RelatedTimeout   time.Duration
+}
+
 type ProductAggregatorService struct {
 	productRepo    port.ProductRepository
 	sellerClient   port.SellerClient
 	reviewClient   port.ReviewClient
 	questionClient port.QuestionClient
+	options        AggregatorOptions
 	logger         *slog.Logger
 }
 
@@ -25,6 +36,7 @@ func NewProductAggregatorService(
 	sellerClient port.SellerClient,
 	reviewClient port.ReviewClient,
 	questionClient port.QuestionClient,
+	options AggregatorOptions,
 	logger *slog.Logger,
 ) *ProductAggregatorService {
 	return &ProductAggregatorService{
@@ -32,11 +44,21 @@ func NewProductAggregatorService(
 		sellerClient:   sellerClient,
 		reviewClient:   reviewClient,
 		questionClient: questionClient,
+		options:        options,
 		logger:         logger,
 	}
 }
 
-func (s *ProductAggregatorService) GetProductDetails(ctx context.Context, productID string) (*model.ProductDetails, error) {
+// reviewSummary es la sección de opiniones: las reseñas y sus totales.
+type reviewSummary struct {
+	items   []model.Review
+	average float64
+	total   int
+}
+
+// GetProductDetails arma el detalle con las secciones pedidas; las demás
+// quedan vacías sin llamar a sus servicios.
+func (s *ProductAggregatorService) GetProductDetails(ctx context.Context, productID string, sections model.DetailSections) (*model.ProductDetails, error) {
 	s.logger.Info("Starting product aggregation", "product_id", productID)
 	start := time.Now()
 
@@ -47,17 +69,25 @@ func (s *ProductAggregatorService) GetProductDetails(ctx context.Context, produc
 		return nil, ErrProductNotFound
 	}
 
-	// PASO 2: Orquestar llamadas asíncronas a "microservicios"
-	s.logger.Info("Orchestrating 4 parallel service calls")
+	// PASO 2: Orquestar llamadas asíncronas a "microservicios", cada una
+	// con su deadline; una sección que falla o vence queda vacía
+	calls := 0
+	for _, section := range model.DetailSectionNames {
+		if sections.Has(section) {
+			calls++
+		}
+	}
+	s.logger.Info("Orchestrating parallel service calls", "calls", calls)
 
 	type result struct {
-		seller    *model.Seller
-		reviews   []model.Review
-		avgRating float64
-		totalRevs int
-		questions []model.Question
-		related   []model.Product
-		err       error
+		seller       *model.Seller
+		sellerErr    error
+		reviews      reviewSummary
+		reviewsErr   error
+		questions    []model.Question
+		questionsErr error
+		related      []model.Product
+		relatedErr   error
 	}
 
 	resultChan := make(chan result, 1)
@@ -68,49 +98,60 @@ func (s *ProductAggregatorService) GetProductDetails(ctx context.Context, produc
 		res := result{}
 
 		// Seller
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			seller, err := s.fetchSeller(ctx, product.ID)
-			mu.Lock()
-			res.seller = seller
-			if err != nil {
-				s.logger.Warn("Seller fetch failed, using default", "error", err)
-			}
-			mu.Unlock()
-		}()
+		if sections.Has(model.SectionSeller) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				seller, err := withDeadline(ctx, s.options.SellerTimeout, func(ctx context.Context) (*model.Seller, error) {
+					return s.fetchSeller(ctx, product.SellerID)
+				})
+				mu.Lock()
+				res.seller, res.sellerErr = seller, err
+				mu.Unlock()
+			}()
+		}
 
 		// Reviews
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			reviews, avg, total := s.fetchReviews(ctx, productID)
-			mu.Lock()
-			res.reviews = reviews
-			res.avgRating = avg
-			res.totalRevs = total
-			mu.Unlock()
-		}()
+		if sections.Has(model.SectionReviews) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reviews, err := withDeadline(ctx, s.options.ReviewsTimeout, func(ctx context.Context) (reviewSummary, error) {
+					return s.fetchReviews(ctx, productID)
+				})
+				mu.Lock()
+				res.reviews, res.reviewsErr = reviews, err
+				mu.Unlock()
+			}()
+		}
 
 		// Questions
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			questions := s.fetchQuestions(ctx, productID)
-			mu.Lock()
-			res.questions = questions
-			mu.Unlock()
-		}()
+		if sections.Has(model.SectionQuestions) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				questions, err := withDeadline(ctx, s.options.QuestionsTimeout, func(ctx context.Context) ([]model.Question, error) {
+					return s.fetchQuestions(ctx, productID)
+				})
+				mu.Lock()
+				res.questions, res.questionsErr = questions, err
+				mu.Unlock()
+			}()
+		}
 
 		// Related Products
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			related := s.fetchRelated(ctx, productID, product.Category)
-			mu.Lock()
-			res.related = related
-			mu.Unlock()
-		}()
+		if sections.Has(model.SectionRelated) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				related, err := withDeadline(ctx, s.options.RelatedTimeout, func(ctx context.Context) ([]model.Product, error) {
+					return s.fetchRelated(ctx, productID, product.Category)
+				})
+				mu.Lock()
+				res.related, res.relatedErr = related, err
+				mu.Unlock()
+			}()
+		}
 
 		wg.Wait()
 		resultChan <- res
@@ -118,49 +159,129 @@ func (s *ProductAggregatorService) GetProductDetails(ctx context.Context, produc
 
 	res := <-resultChan
 
+	var degraded []model.DegradedSection
+	degrade := func(section string, err error) {
+		reason := degradedReason(err)
+		s.logger.Warn("Section degraded",
+			"product_id", productID,
+			"section", section,
+			"reason", reason,
+			"error", err,
+		)
+		degraded = append(degraded, model.DegradedSection{Section: section, Reason: reason})
+	}
+
+	if res.sellerErr != nil {
+		degrade(model.SectionSeller, res.sellerErr)
+		res.seller = defaultSeller()
+	}
+	if res.reviewsErr != nil {
+		degrade(model.SectionReviews, res.reviewsErr)
+		res.reviews = reviewSummary{}
+	}
+	if res.questionsErr != nil {
+		degrade(model.SectionQuestions, res.questionsErr)
+		res.questions = nil
+	}
+	if res.relatedErr != nil {
+		degrade(model.SectionRelated, res.relatedErr)
+		res.related = nil
+	}
+
 	// PASO 3: Calcular shipping
 	shipping := s.buildShipping(product)
 
 	// PASO 4: Construir respuesta agregada
 	details := &model.ProductDetails{
 		Product:         *product,
-		Seller:          *res.seller,
 		Shipping:        shipping,
-		Reviews:         res.reviews,
-		AverageRating:   res.avgRating,
-		TotalReviews:    res.totalRevs,
+		Reviews:         res.reviews.items,
+		AverageRating:   res.reviews.average,
+		TotalReviews:    res.reviews.total,
 		Questions:       res.questions,
 		RelatedProducts: res.related,
+		Degraded:        degraded,
+	}
+	if res.seller != nil {
+		details.Seller = *res.seller
 	}
 
 	duration := time.Since(start)
 	s.logger.Info("Aggregation completed",
 		"product_id", productID,
 		"duration_ms", duration.Milliseconds(),
-		"reviews", len(res.reviews),
+		"reviews", len(res.reviews.items),
 		"questions", len(res.questions),
 		"related", len(res.related),
+		"degraded", len(degraded),
 	)
 
 	return details, nil
 }
 
+// withDeadline ejecuta fn con un contexto que vence en timeout (0 deja el
+// del request). Si el contexto vence primero devuelve su error sin esperar
+// a fn, por si el adaptador no respeta ctx.
+func withDeadline[T any](ctx context.Context, timeout time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := fn(ctx)
+		done <- outcome{value, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func degradedReason(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return model.DegradedTimeout
+	case errors.Is(err, port.ErrSellerNotFound),
+		errors.Is(err, port.ErrReviewsNotFound),
+		errors.Is(err, port.ErrQuestionsNotFound):
+		return model.DegradedNotFound
+	case errors.Is(err, port.ErrCircuitOpen):
+		return model.DegradedCircuitOpen
+	}
+	return model.DegradedError
+}
+
+// defaultSeller reemplaza al vendedor cuando no se pudo obtener.
+func defaultSeller() *model.Seller {
+	return &model.Seller{
+		ID:              "default",
+		Nickname:        "Vendedor",
+		ReputationLevel: "green",
+		TotalSales:      0,
+		ReputationScore: 0.0,
+		YearsActive:     0,
+		IsOfficialStore: false,
+	}
+}
+
 func (s *ProductAggregatorService) fetchSeller(ctx context.Context, sellerID string) (*model.Seller, error) {
 	s.logger.Debug("Calling SellerService", "seller_id", sellerID)
 	start := time.Now()
 
 	seller, err := s.sellerClient.GetByID(ctx, sellerID)
 	if err != nil {
-		// Fallback: Default seller
-		return &model.Seller{
-			ID:              "default",
-			Nickname:        "Vendedor",
-			ReputationLevel: "green",
-			TotalSales:      0,
-			ReputationScore: 0.0,
-			YearsActive:     0,
-			IsOfficialStore: false,
-		}, err
+		return nil, err
 	}
 
 	s.logger.Debug("SellerService responded",
@@ -171,13 +292,22 @@ func (s *ProductAggregatorService) fetchSeller(ctx context.Context, sellerID str
 	return seller, nil
 }
 
-func (s *ProductAggregatorService) fetchReviews(ctx context.Context, productID string) ([]model.Review, float64, int) {
+func (s *ProductAggregatorService) fetchReviews(ctx context.Context, productID string) (reviewSummary, error) {
 	s.logger.Debug("Calling ReviewService", "product_id", productID)
 	start := time.Now()
 
-	reviews, _ := s.reviewClient.GetByProductID(ctx, productID)
-	avgRating, _ := s.reviewClient.GetAverageRating(ctx, productID)
-	total, _ := s.reviewClient.GetTotalCount(ctx, productID)
+	reviews, err := s.reviewClient.GetByProductID(ctx, productID)
+	if err != nil {
+		return reviewSummary{}, err
+	}
+	avgRating, err := s.reviewClient.GetAverageRating(ctx, productID)
+	if err != nil {
+		return reviewSummary{}, err
+	}
+	total, err := s.reviewClient.GetTotalCount(ctx, productID)
+	if err != nil {
+		return reviewSummary{}, err
+	}
 
 	s.logger.Debug("ReviewService responded",
 		"duration_ms", time.Since(start).Milliseconds(),
@@ -185,39 +315,45 @@ func (s *ProductAggregatorService) fetchReviews(ctx context.Context, productID s
 		"avg_rating", avgRating,
 	)
 
-	return reviews, avgRating, total
+	return reviewSummary{items: reviews, average: avgRating, total: total}, nil
 }
 
-func (s *ProductAggregatorService) fetchQuestions(ctx context.Context, productID string) []model.Question {
+func (s *ProductAggregatorService) fetchQuestions(ctx context.Context, productID string) ([]model.Question, error) {
 	s.logger.Debug("Calling QuestionService", "product_id", productID)
 	start := time.Now()
 
-	questions, _ := s.questionClient.GetByProductID(ctx, productID, 10)
+	questions, err := s.questionClient.GetByProductID(ctx, productID, 10)
+	if err != nil {
+		return nil, err
+	}
 
 	s.logger.Debug("QuestionService responded",
 		"duration_ms", time.Since(start).Milliseconds(),
 		"count", len(questions),
 	)
 
-	return questions
+	return questions, nil
 }
 
-func (s *ProductAggregatorService) fetchRelated(ctx context.Context, productID, category string) []model.Product {
+func (s *ProductAggregatorService) fetchRelated(ctx context.Context, productID, category string) ([]model.Product, error) {
 	s.logger.Debug("Calling ProductService for related", "category", category)
 	start := time.Now()
 
-	related, _ := s.productRepo.FindRelated(ctx, productID, category, 4)
+	related, err := s.productRepo.FindRelated(ctx, productID, category, 4)
+	if err != nil {
+		return nil, err
+	}
 
 	s.logger.Debug("ProductService responded",
 		"duration_ms", time.Since(start).Milliseconds(),
 		"count", len(related),
 	)
 
-	return related
+	return related, nil
 }
 
 func (s *ProductAggregatorService) buildShipping(product *model.Product) model.Shipping {
-	freeShipping := product.Price > 50000
+	freeShipping := product.HasFreeShipping()
 	cost := 0.0
 	if !freeShipping {
 		cost = product.Price * 0.05
@@ -2,67 +2,406 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"meli-product-api/internal/domain/analysis"
 	"meli-product-api/internal/domain/model"
 	"meli-product-api/internal/domain/port"
+	"meli-product-api/internal/domain/query"
 	"strings"
+	"time"
 )
 
+var (
+	// ErrCursorMismatch indica un cursor emitido para otro orden de resultados.
+	ErrCursorMismatch = errors.New("cursor does not match the requested sort order")
+	// ErrNothingToSearch indica una query sin términos buscables y sin
+	// categoría ni marca para navegar.
+	ErrNothingToSearch = errors.New("query has no searchable terms")
+)
+
+// SearchOptions agrupa los ajustes de comportamiento de la búsqueda.
+type SearchOptions struct {
+	// FuzzyMinResults: si la búsqueda exacta trae menos resultados se
+	// reintenta tolerando errores de tipeo. 0 lo desactiva.
+	FuzzyMinResults int
+	// AutoCorrect ejecuta directamente la sugerencia ortográfica cuando la
+	// query original no trae resultados.
+	AutoCorrect bool
+	// HistogramBuckets es la cantidad de intervalos del histograma de
+	// precios cuando el pedido no indica otra.
+	HistogramBuckets int
+}
+
 type ProductSearchService struct {
-	productRepo port.ProductRepository
-	logger      *slog.Logger
+	productRepo  port.ProductRepository
+	spellChecker port.SpellChecker
+	synonyms     port.SynonymSource
+	brands       port.BrandCatalog
+	analytics    port.SearchAnalytics
+	analyzer     *analysis.Analyzer
+	options      SearchOptions
+	logger       *slog.Logger
 }
 
 func NewProductSearchService(
 	productRepo port.ProductRepository,
+	spellChecker port.SpellChecker,
+	synonyms port.SynonymSource,
+	brands port.BrandCatalog,
+	analytics port.SearchAnalytics,
+	analyzer *analysis.Analyzer,
+	options SearchOptions,
 	logger *slog.Logger,
 ) *ProductSearchService {
 	return &ProductSearchService{
-		productRepo: productRepo,
-		logger:      logger,
+		productRepo:  productRepo,
+		spellChecker: spellChecker,
+		synonyms:     synonyms,
+		brands:       brands,
+		analytics:    analytics,
+		analyzer:     analyzer,
+		options:      options,
+		logger:       logger,
 	}
 }
 
-func (s *ProductSearchService) Search(ctx context.Context, query string, limit, offset int) ([]model.Product, int, error) {
+// Search ejecuta la búsqueda y la registra en la analítica. Solo cuenta la
+// primera página: paginar no es buscar de nuevo.
+func (s *ProductSearchService) Search(ctx context.Context, criteria model.SearchCriteria, page model.Page) (*model.SearchResult, error) {
+	start := time.Now()
+
+	result, err := s.search(ctx, criteria, page)
+	if err == nil && page.After == nil && page.Offset == 0 {
+		s.record(ctx, criteria, result.Total, time.Since(start))
+	}
+
+	return result, err
+}
+
+func (s *ProductSearchService) search(ctx context.Context, criteria model.SearchCriteria, page model.Page) (*model.SearchResult, error) {
 	s.logger.Info("Starting product search",
-		"query", query,
-		"limit", limit,
-		"offset", offset,
+		"query", criteria.Keyword,
+		"sort", criteria.Sort,
+		"limit", page.Limit,
+		"offset", page.Offset,
+		"cursor", page.After != nil,
 	)
 
-	// Validate and sanitize query
-	query = strings.TrimSpace(query)
-	if query == "" {
-		s.logger.Warn("Empty search query provided")
-		return []model.Product{}, 0, nil
+	criteria, interpretation, err := s.prepare(ctx, criteria)
+	if errors.Is(err, ErrNothingToSearch) {
+		s.logger.Info("Query has no searchable terms", "query", criteria.Keyword)
+		return &model.SearchResult{Hits: []model.SearchHit{}}, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	keyword := criteria.Keyword
 
-	query = strings.ToLower(query)
+	if page.After != nil && page.After.Sort != criteria.Sort {
+		return nil, ErrCursorMismatch
+	}
+	if criteria.Histogram.Buckets == 0 && criteria.Histogram.Interval == 0 {
+		criteria.Histogram.Buckets = s.options.HistogramBuckets
+	}
 
 	// Count total results
-	total, err := s.productRepo.Count(ctx, query)
+	criteria, total, err := s.count(ctx, criteria)
 	if err != nil {
-		s.logger.Error("Failed to count results", "error", err)
-		return nil, 0, err
+		return nil, err
 	}
 
+	result := &model.SearchResult{Hits: []model.SearchHit{}, Interpretation: interpretation}
+
 	if total == 0 {
-		s.logger.Info("No products found", "query", query)
-		return []model.Product{}, 0, nil
+		s.logger.Info("No products found", "query", keyword)
+
+		corrected, correctedTotal, err := s.correct(ctx, criteria)
+		if err != nil {
+			return nil, err
+		}
+		if correctedTotal == 0 {
+			return result, nil
+		}
+
+		result.Suggestion = corrected.Keyword
+		if !s.options.AutoCorrect {
+			return result, nil
+		}
+
+		s.logger.Info("Running corrected query", "query", keyword, "corrected", corrected.Keyword)
+		result.AutoCorrected = true
+		criteria = corrected
 	}
 
-	// Search products
-	products, err := s.productRepo.Search(ctx, query, limit, offset)
+	// Search products; the extra hit tells whether there is a next page.
+	// Facets cover the full match set, not only the current page
+	criteria.Facets = true
+	lookahead := page
+	lookahead.Limit++
+	found, err := s.productRepo.Search(ctx, criteria, lookahead)
+	if errors.Is(err, model.ErrCursorExpired) {
+		s.logger.Info("Relevance cursor expired", "query", keyword)
+		return nil, err
+	}
 	if err != nil {
 		s.logger.Error("Search failed", "error", err)
-		return nil, 0, err
+		return nil, err
+	}
+	hits := found.Hits
+	if page.Limit > 0 && len(hits) > page.Limit {
+		hits = hits[:page.Limit]
+		next := criteria.Sort.CursorAt(hits[len(hits)-1])
+		result.Next = &next
 	}
 
+	// The search's own totals win over the earlier count: the catalog may
+	// have changed in between. Grouped searches paginate over groups but
+	// still report listings
+	result.Hits = hits
+	result.Total = found.Total
+	result.Groups = found.Groups
+	result.Facets = found.Facets
+	result.PriceHistogram = found.PriceHistogram
+
 	s.logger.Info("Search completed",
-		"query", query,
-		"results", len(products),
-		"total", total,
+		"query", criteria.Keyword,
+		"results", len(hits),
+		"total", result.Total,
+		"groups", result.Groups,
 	)
 
-	return products, total, nil
+	return result, nil
+}
+
+// Prepare interpreta y compila los criterios tal como lo hace Search, para
+// evaluarlos por fuera de una búsqueda (p. ej. búsquedas guardadas).
+// Devuelve un *query.SyntaxError si la query es inválida y
+// ErrNothingToSearch si no hay nada que buscar.
+func (s *ProductSearchService) Prepare(ctx context.Context, criteria model.SearchCriteria) (model.SearchCriteria, error) {
+	criteria, _, err := s.prepare(ctx, criteria)
+	return criteria, err
+}
+
+func (s *ProductSearchService) prepare(ctx context.Context, criteria model.SearchCriteria) (model.SearchCriteria, *model.Interpretation, error) {
+	// Validate and sanitize query
+	criteria.Keyword = strings.TrimSpace(criteria.Keyword)
+	criteria, interpretation := s.interpret(ctx, criteria)
+	keyword := criteria.Keyword
+
+	var node query.Node
+	if keyword != "" {
+		var err error
+		if node, err = query.Compile(keyword, s.analyzer); err != nil {
+			s.logger.Warn("Invalid search query", "query", keyword, "error", err)
+			return criteria, nil, err
+		}
+	}
+
+	if criteria.Sort == "" {
+		criteria.Sort = model.SortRelevance
+	}
+
+	if node != nil {
+		criteria.Query = s.expandSynonyms(ctx, node)
+		return criteria, interpretation, nil
+	}
+
+	// Without searchable words a category, brand or seller still defines
+	// a listing to browse; with none there is nothing to search
+	if !criteria.Filters.Scoped() {
+		return criteria, nil, ErrNothingToSearch
+	}
+	s.logger.Info("Browsing without keyword", "filters", criteria.Filters.String())
+
+	criteria.Query = nil
+	criteria.Highlight = false
+	// Every product scores the same, so best sellers stand in for relevance
+	if criteria.Sort == model.SortRelevance {
+		criteria.Sort = model.SortBestSelling
+	}
+	return criteria, interpretation, nil
+}
+
+func (s *ProductSearchService) record(ctx context.Context, criteria model.SearchCriteria, results int, latency time.Duration) {
+	if s.analytics == nil {
+		return
+	}
+
+	// Browse searches keep an empty query so their filters and latency
+	// are still reported
+	normalized := strings.Join(strings.Fields(analysis.Fold(criteria.Keyword)), " ")
+
+	event := model.SearchEvent{
+		Query:   normalized,
+		Filters: criteria.Filters.String(),
+		Results: results,
+		Latency: latency,
+		At:      time.Now(),
+	}
+	if err := s.analytics.Record(ctx, event); err != nil {
+		s.logger.Warn("Failed to record search analytics", "error", err)
+	}
+}
+
+// interpret convierte precios, condición y marca escritos en la query en
+// filtros y los quita del texto. No se aplica si la query usa sintaxis de
+// búsqueda o si sin esas palabras no quedaría nada para buscar ni navegar.
+func (s *ProductSearchService) interpret(ctx context.Context, criteria model.SearchCriteria) (model.SearchCriteria, *model.Interpretation) {
+	if criteria.Keyword == "" || !isPlainQuery(criteria.Keyword) {
+		return criteria, nil
+	}
+
+	var brands []string
+	if s.brands != nil && !criteria.SkipInterpretation[model.InterpretBrand] {
+		var err error
+		if brands, err = s.brands.Brands(ctx); err != nil {
+			s.logger.Warn("Failed to load brands, skipping brand detection", "error", err)
+		}
+	}
+
+	filters, interpretation := interpretQuery(criteria.Keyword, criteria.Filters, brands, criteria.SkipInterpretation)
+	if interpretation == nil {
+		return criteria, nil
+	}
+
+	if !filters.Scoped() {
+		node, err := query.Compile(interpretation.Keyword, s.analyzer)
+		if interpretation.Keyword == "" || err != nil || node == nil {
+			return criteria, nil
+		}
+	}
+
+	s.logger.Info("Query interpreted",
+		"query", criteria.Keyword,
+		"keyword", interpretation.Keyword,
+		"filters", len(interpretation.Filters),
+	)
+
+	criteria.Keyword = interpretation.Keyword
+	criteria.Filters = filters
+	return criteria, interpretation
+}
+
+// expandSynonyms agrega los sinónimos vigentes a la query. Si no se pueden
+// obtener, la búsqueda sigue sin ellos.
+func (s *ProductSearchService) expandSynonyms(ctx context.Context, node query.Node) query.Node {
+	if s.synonyms == nil {
+		return node
+	}
+
+	synonyms, err := s.synonyms.Synonyms(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load synonyms", "error", err)
+		return node
+	}
+	return synonyms.Expand(node)
+}
+
+// count cuenta los resultados y, si son pocos, reintenta con fuzzy
+// matching. Devuelve los criterios que efectivamente conviene usar.
+func (s *ProductSearchService) count(ctx context.Context, criteria model.SearchCriteria) (model.SearchCriteria, int, error) {
+	total, err := s.productRepo.Count(ctx, criteria)
+	if err != nil {
+		s.logger.Error("Failed to count results", "error", err)
+		return criteria, 0, err
+	}
+
+	// Typo tolerance only kicks in when exact matching falls short, so it
+	// never dilutes a good result set
+	if total >= s.options.FuzzyMinResults || criteria.Query == nil {
+		return criteria, total, nil
+	}
+
+	fuzzy := criteria
+	fuzzy.Fuzzy = true
+
+	fuzzyTotal, err := s.productRepo.Count(ctx, fuzzy)
+	if err != nil {
+		s.logger.Error("Failed to count fuzzy results", "error", err)
+		return criteria, 0, err
+	}
+
+	if fuzzyTotal <= total {
+		return criteria, total, nil
+	}
+
+	s.logger.Info("Using fuzzy matching",
+		"query", criteria.Keyword,
+		"exact_total", total,
+		"fuzzy_total", fuzzyTotal,
+	)
+	return fuzzy, fuzzyTotal, nil
+}
+
+// correct arma la query corregida ("¿quisiste decir...?") reemplazando
+// cada palabra desconocida por la sugerencia del diccionario, y cuenta
+// cuántos resultados tendría. Solo se corrigen los términos positivos:
+// operadores, calificadores y exclusiones quedan como estaban. Devuelve
+// total 0 si no hay una corrección útil.
+func (s *ProductSearchService) correct(ctx context.Context, criteria model.SearchCriteria) (model.SearchCriteria, int, error) {
+	keyword := criteria.Keyword
+
+	var tokens []analysis.Token
+	var words []string
+	for _, leaf := range query.Leaves(criteria.Query) {
+		if leaf.Synonym {
+			continue
+		}
+		for _, tok := range s.analyzer.Tokenize(keyword[leaf.Start:leaf.End]) {
+			if s.analyzer.IsStopword(tok.Term) {
+				continue
+			}
+			tok.Start += leaf.Start
+			tok.End += leaf.Start
+			tokens = append(tokens, tok)
+			words = append(words, tok.Term)
+		}
+	}
+	if len(words) == 0 {
+		return criteria, 0, nil
+	}
+
+	corrections, err := s.spellChecker.Correct(ctx, words)
+	if err != nil {
+		s.logger.Warn("Spell check failed", "error", err)
+		return criteria, 0, nil
+	}
+
+	var b strings.Builder
+	last, changed := 0, false
+	for i, tok := range tokens {
+		replacement := strings.Join(corrections[i], " ")
+		if replacement == tok.Term {
+			continue
+		}
+		b.WriteString(keyword[last:tok.Start])
+		b.WriteString(replacement)
+		last, changed = tok.End, true
+	}
+	if !changed {
+		return criteria, 0, nil
+	}
+	b.WriteString(keyword[last:])
+
+	corrected := criteria
+	corrected.Keyword = b.String()
+	corrected.Fuzzy = false
+	node, err := query.Compile(corrected.Keyword, s.analyzer)
+	if err != nil || node == nil {
+		return criteria, 0, nil
+	}
+	corrected.Query = s.expandSynonyms(ctx, node)
+
+	corrected, total, err := s.count(ctx, corrected)
+	if err != nil {
+		return criteria, 0, err
+	}
+
+	s.logger.Info("Spelling suggestion",
+		"query", keyword,
+		"suggestion", corrected.Keyword,
+		"total", total,
+	)
+	return corrected, total, nil
 }
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"strings"
+	"time"
+)
+
+type ProductSuggestService struct {
+	suggester port.ProductSuggester
+	logger    *slog.Logger
+}
+
+func NewProductSuggestService(
+	suggester port.ProductSuggester,
+	logger *slog.Logger,
+) *ProductSuggestService {
+	return &ProductSuggestService{
+		suggester: suggester,
+		logger:    logger,
+	}
+}
+
+func (s *ProductSuggestService) Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []model.Suggestion{}, nil
+	}
+
+	start := time.Now()
+
+	suggestions, err := s.suggester.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.Error("Suggest failed", "prefix", prefix, "error", err)
+		return nil, err
+	}
+
+	s.logger.Debug("Suggest completed",
+		"prefix", prefix,
+		"results", len(suggestions),
+		"duration_us", time.Since(start).Microseconds(),
+	)
+
+	return suggestions, nil
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Frases que anteceden a un monto y lo convierten en tope o piso de precio.
+var (
+	priceMaxPrefixes = [][]string{
+		{"menos", "de"},
+		{"hasta"},
+		{"maximo"},
+		{"max"},
+		{"por", "debajo", "de"},
+		{"no", "mas", "de"},
+	}
+	priceMinPrefixes = [][]string{
+		{"mas", "de"},
+		{"desde"},
+		{"minimo"},
+		{"arriba", "de"},
+		{"por", "encima", "de"},
+	}
+)
+
+// amountMultipliers son las palabras que siguen a un número y lo escalan
+// ("600 mil", "1,5 millones", "300 lucas").
+var amountMultipliers = map[string]float64{
+	"mil":      1e3,
+	"luca":     1e3,
+	"lucas":    1e3,
+	"millon":   1e6,
+	"millones": 1e6,
+	"palo":     1e6,
+	"palos":    1e6,
+}
+
+// currencyWords pueden seguir al monto y se consideran parte de él.
+var currencyWords = map[string]bool{"pesos": true, "ars": true}
+
+// measureUnits delatan que el número no es un precio ("menos de 3 kg",
+// "hasta 12 cuotas").
+var measureUnits = map[string]bool{
+	"gb": true, "tb": true, "mb": true, "kg": true, "kilos": true, "g": true, "gramos": true,
+	"cm": true, "mm": true, "m": true, "metros": true, "pulgadas": true, "w": true, "watts": true,
+	"mah": true, "hz": true, "mp": true, "l": true, "litros": true, "ml": true,
+	"anos": true, "meses": true, "dias": true, "horas": true, "cuotas": true,
+}
+
+var conditionWords = map[string]string{
+	"nuevo": "new", "nueva": "new", "nuevos": "new", "nuevas": "new", "0km": "new",
+	"usado": "used", "usada": "used", "usados": "used", "usadas": "used",
+}
+
+var (
+	// 500.000 / 1,500,000: separadores de miles
+	thousandsNumber = regexp.MustCompile(`^\d{1,3}([.,]\d{3})+$`)
+	// 500000 / 1,5 / 2.25: a lo sumo dos decimales
+	plainNumber = regexp.MustCompile(`^\d+([.,]\d{1,2})?$`)
+)
+
+// queryWord es una palabra de la query: Text plegado (minúsculas, sin
+// tildes ni signos alrededor) y su posición en bytes en el original.
+type queryWord struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// extraction es un filtro reconocido en las palabras [from, to).
+type extraction struct {
+	filter   model.InterpretedFilter
+	from, to int
+}
+
+// isPlainQuery reporta si la query es texto libre, sin la sintaxis de
+// búsqueda (frases, grupos, exclusiones, operadores ni calificadores); solo
+// esas se interpretan.
+func isPlainQuery(keyword string) bool {
+	if strings.ContainsAny(keyword, `"():`) {
+		return false
+	}
+	for _, w := range strings.Fields(keyword) {
+		if w == "OR" || w == "AND" || strings.HasPrefix(w, "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// interpretQuery busca en keyword precios, condición y marca, los agrega a
+// filters y los quita del texto. No pisa filtros que ya vinieron
+// explícitos ni interpreta los tipos de skip. Devuelve nil si no reconoció
+// nada.
+func interpretQuery(keyword string, filters model.SearchFilters, brands []string, skip map[string]bool) (model.SearchFilters, *model.Interpretation) {
+	words := splitQueryWords(keyword)
+	used := make([]bool, len(words))
+
+	var found []extraction
+	claim := func(e extraction) {
+		for i := e.from; i < e.to; i++ {
+			used[i] = true
+		}
+		e.filter.Text = keyword[words[e.from].Start:words[e.to-1].End]
+		found = append(found, e)
+	}
+
+	if !skip[model.InterpretPrice] && filters.PriceMin == nil && filters.PriceMax == nil {
+		for _, e := range extractPrices(words, used, &filters) {
+			claim(e)
+		}
+	}
+	if !skip[model.InterpretCondition] && filters.Condition == "" {
+		if e, ok := extractCondition(words, used); ok {
+			filters.Condition = e.filter.Value
+			claim(e)
+		}
+	}
+	if !skip[model.InterpretBrand] && filters.Brand == "" {
+		if e, ok := extractBrand(words, used, brands); ok {
+			filters.Brand = e.filter.Value
+			claim(e)
+		}
+	}
+
+	if len(found) == 0 {
+		return filters, nil
+	}
+
+	// Chips follow the order in which the user wrote them
+	interpretation := &model.Interpretation{}
+	for i := range words {
+		for _, e := range found {
+			if e.from == i {
+				interpretation.Filters = append(interpretation.Filters, e.filter)
+			}
+		}
+	}
+
+	var rest strings.Builder
+	last := 0
+	for i, w := range words {
+		if used[i] {
+			rest.WriteString(keyword[last:w.Start])
+			rest.WriteByte(' ')
+			last = w.End
+		}
+	}
+	rest.WriteString(keyword[last:])
+	interpretation.Keyword = strings.Join(strings.Fields(rest.String()), " ")
+
+	return filters, interpretation
+}
+
+func splitQueryWords(keyword string) []queryWord {
+	var words []queryWord
+	start := -1
+	for i, r := range keyword + " " {
+		if !unicode.IsSpace(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			text := strings.Trim(analysis.Fold(keyword[start:i]), ",;.!?¿¡")
+			words = append(words, queryWord{Text: text, Start: start, End: i})
+			start = -1
+		}
+	}
+	return words
+}
+
+// extractPrices reconoce "menos de X", "desde X", "entre X y Y" y sus
+// variantes, a lo sumo un piso y un tope.
+func extractPrices(words []queryWord, used []bool, filters *model.SearchFilters) []extraction {
+	var found []extraction
+
+	for i := 0; i < len(words); i++ {
+		if used[i] {
+			continue
+		}
+
+		if words[i].Text == "entre" && filters.PriceMin == nil && filters.PriceMax == nil {
+			low, n, ok := parseAmount(words, i+1)
+			if ok && i+1+n < len(words) && words[i+1+n].Text == "y" {
+				if high, m, ok := parseAmount(words, i+2+n); ok {
+					low, high = min(low, high), max(low, high)
+					filters.PriceMin, filters.PriceMax = &low, &high
+					to := i + 2 + n + m
+					between := model.InterpretedFilter{
+						Kind:  model.InterpretPrice,
+						Param: "price_range",
+						Value: formatPrice(low) + "-" + formatPrice(high),
+					}
+					found = append(found, extraction{filter: between, from: i, to: to})
+					i = to - 1
+					continue
+				}
+			}
+		}
+
+		if filters.PriceMax == nil {
+			if amount, to, ok := prefixedAmount(words, i, priceMaxPrefixes); ok && (filters.PriceMin == nil || *filters.PriceMin <= amount) {
+				filters.PriceMax = &amount
+				found = append(found, extraction{filter: priceFilter("price_max", amount), from: i, to: to})
+				i = to - 1
+				continue
+			}
+		}
+
+		if filters.PriceMin == nil {
+			if amount, to, ok := prefixedAmount(words, i, priceMinPrefixes); ok && (filters.PriceMax == nil || *filters.PriceMax >= amount) {
+				filters.PriceMin = &amount
+				found = append(found, extraction{filter: priceFilter("price_min", amount), from: i, to: to})
+				i = to - 1
+			}
+		}
+	}
+
+	return found
+}
+
+func priceFilter(param string, amount float64) model.InterpretedFilter {
+	return model.InterpretedFilter{Kind: model.InterpretPrice, Param: param, Value: formatPrice(amount)}
+}
+
+func formatPrice(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// prefixedAmount prueba cada prefijo en la posición i seguido de un monto;
+// devuelve el monto y dónde termina la frase.
+func prefixedAmount(words []queryWord, i int, prefixes [][]string) (float64, int, bool) {
+	for _, prefix := range prefixes {
+		if !hasWords(words, i, prefix) {
+			continue
+		}
+		if amount, n, ok := parseAmount(words, i+len(prefix)); ok {
+			return amount, i + len(prefix) + n, true
+		}
+	}
+	return 0, 0, false
+}
+
+func hasWords(words []queryWord, i int, want []string) bool {
+	if i+len(want) > len(words) {
+		return false
+	}
+	for j, w := range want {
+		if words[i+j].Text != w {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAmount lee un monto desde la posición i: "$500.000", "600 mil",
+// "1,5 millones", "300k", "450000 pesos". Devuelve cuántas palabras usó.
+func parseAmount(words []queryWord, i int) (float64, int, bool) {
+	n := 0
+	if i < len(words) && words[i].Text == "$" {
+		n++
+	}
+	if i+n >= len(words) {
+		return 0, 0, false
+	}
+
+	text := strings.TrimPrefix(words[i+n].Text, "$")
+	scale := 1.0
+	if trimmed, ok := strings.CutSuffix(text, "k"); ok {
+		text, scale = trimmed, 1e3
+	}
+	amount, ok := parseNumber(text)
+	if !ok {
+		return 0, 0, false
+	}
+	n++
+
+	if i+n < len(words) && scale == 1 {
+		if m, ok := amountMultipliers[words[i+n].Text]; ok {
+			scale = m
+			n++
+		}
+	}
+	if i+n < len(words) {
+		next := words[i+n].Text
+		if currencyWords[next] {
+			n++
+		} else if measureUnits[next] {
+			return 0, 0, false
+		}
+	}
+
+	amount *= scale
+	if amount <= 0 {
+		return 0, 0, false
+	}
+	return amount, n, true
+}
+
+func parseNumber(text string) (float64, bool) {
+	switch {
+	case thousandsNumber.MatchString(text):
+		text = strings.NewReplacer(".", "", ",", "").Replace(text)
+	case plainNumber.MatchString(text):
+		text = strings.Replace(text, ",", ".", 1)
+	default:
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	return value, err == nil
+}
+
+// extractCondition reconoce "nuevo" o "usado" (y variantes). Si la query
+// menciona las dos no interpreta ninguna.
+func extractCondition(words []queryWord, used []bool) (extraction, bool) {
+	var found extraction
+	ok := false
+	for i, w := range words {
+		condition, isCondition := conditionWords[w.Text]
+		if used[i] || !isCondition {
+			continue
+		}
+		if ok && found.filter.Value != condition {
+			return extraction{}, false
+		}
+		if !ok {
+			found = extraction{
+				filter: model.InterpretedFilter{Kind: model.InterpretCondition, Param: "condition", Value: condition},
+				from:   i,
+				to:     i + 1,
+			}
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+// extractBrand reconoce una marca del catálogo, aunque tenga varias
+// palabras. Si la query menciona más de una marca no interpreta ninguna:
+// "samsung o apple" no es un filtro.
+func extractBrand(words []queryWord, used []bool, brands []string) (extraction, bool) {
+	var found extraction
+	ok := false
+	for _, brand := range brands {
+		want := strings.Fields(analysis.Fold(brand))
+		if len(want) == 0 {
+			continue
+		}
+		for i := range words {
+			if !hasWords(words, i, want) || anyUsed(used, i, i+len(want)) {
+				continue
+			}
+			if ok && found.filter.Value != brand {
+				return extraction{}, false
+			}
+			if !ok {
+				found = extraction{
+					filter: model.InterpretedFilter{Kind: model.InterpretBrand, Param: "brand", Value: brand},
+					from:   i,
+					to:     i + len(want),
+				}
+				ok = true
+			}
+		}
+	}
+	return found, ok
+}
+
+func anyUsed(used []bool, from, to int) bool {
+	for i := from; i < to; i++ {
+		if used[i] {
+			return true
+		}
+	}
+	return false
+}
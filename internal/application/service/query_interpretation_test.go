@@ -0,0 +1,105 @@
+package service
+
+import (
+	"meli-product-api/internal/domain/model"
+	"testing"
+)
+
+func TestInterpretQuery(t *testing.T) {
+	brands := []string{"Apple", "Samsung", "Mercado Libre"}
+
+	type filter struct{ param, value, text string }
+
+	tests := []struct {
+		name        string
+		keyword     string
+		filters     model.SearchFilters
+		skip        map[string]bool
+		wantKeyword string
+		wantFilters []filter
+		wantMin     float64
+		wantMax     float64
+	}{
+		{
+			name:        "price max with thousands separator",
+			keyword:     "celular samsung menos de $500.000",
+			wantKeyword: "celular",
+			wantFilters: []filter{{"brand", "Samsung", "samsung"}, {"price_max", "500000", "menos de $500.000"}},
+			wantMax:     500000,
+		},
+		{
+			name:        "price range with multiplier",
+			keyword:     "notebook entre 600 mil y 1,5 millones",
+			wantKeyword: "notebook",
+			wantFilters: []filter{{"price_range", "600000-1500000", "entre 600 mil y 1,5 millones"}},
+			wantMin:     600000,
+			wantMax:     1500000,
+		},
+		{
+			name:        "price min and condition",
+			keyword:     "iphone usado desde 300k",
+			wantKeyword: "iphone",
+			wantFilters: []filter{{"condition", "used", "usado"}, {"price_min", "300000", "desde 300k"}},
+			wantMin:     300000,
+		},
+		{
+			name:        "multi-word brand",
+			keyword:     "tarjeta mercado libre nueva",
+			wantKeyword: "tarjeta",
+			wantFilters: []filter{{"brand", "Mercado Libre", "mercado libre"}, {"condition", "new", "nueva"}},
+		},
+		{name: "measure unit is not a price", keyword: "mochila hasta 3 kg"},
+		{name: "two brands are not a filter", keyword: "samsung o apple"},
+		{name: "conflicting conditions are not a filter", keyword: "nuevo o usado"},
+		{
+			name:    "explicit filters win",
+			keyword: "samsung hasta 1000",
+			filters: model.SearchFilters{Brand: "Apple", PriceMax: ptr(2000.0)},
+			wantMax: 2000,
+		},
+		{
+			name:        "skipped kinds are kept as text",
+			keyword:     "samsung hasta 1000",
+			skip:        map[string]bool{model.InterpretPrice: true},
+			wantKeyword: "hasta 1000",
+			wantFilters: []filter{{"brand", "Samsung", "samsung"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, got := interpretQuery(tt.keyword, tt.filters, brands, tt.skip)
+
+			if got == nil {
+				if tt.wantFilters != nil {
+					t.Fatalf("interpretQuery() = nil, want %v", tt.wantFilters)
+				}
+			} else {
+				if got.Keyword != tt.wantKeyword {
+					t.Errorf("Keyword = %q, want %q", got.Keyword, tt.wantKeyword)
+				}
+				if len(got.Filters) != len(tt.wantFilters) {
+					t.Fatalf("Filters = %+v, want %v", got.Filters, tt.wantFilters)
+				}
+				for i, f := range got.Filters {
+					if want := tt.wantFilters[i]; f.Param != want.param || f.Value != want.value || f.Text != want.text {
+						t.Errorf("Filters[%d] = %+v, want %v", i, f, want)
+					}
+				}
+			}
+
+			if value(filters.PriceMin) != tt.wantMin || value(filters.PriceMax) != tt.wantMax {
+				t.Errorf("price range = %v-%v, want %v-%v", value(filters.PriceMin), value(filters.PriceMax), tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func value(p *float64) float64 {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxSavedSearchesPerUser acota cuántas búsquedas puede guardar un usuario.
+const MaxSavedSearchesPerUser = 50
+
+// SavedSearchService administra las búsquedas guardadas y, en segundo
+// plano, las evalúa contra los productos que se agregan o modifican.
+type SavedSearchService struct {
+	savedSearches port.SavedSearchRepository
+	notifications port.NotificationRepository
+	matcher       port.ProductMatcher
+	productRepo   port.ProductRepository
+	searchService *ProductSearchService
+	logger        *slog.Logger
+
+	mu      sync.Mutex
+	pending map[string]string // producto -> tipo de cambio, aún sin evaluar
+	wake    chan struct{}
+}
+
+func NewSavedSearchService(
+	savedSearches port.SavedSearchRepository,
+	notifications port.NotificationRepository,
+	matcher port.ProductMatcher,
+	productRepo port.ProductRepository,
+	searchService *ProductSearchService,
+	logger *slog.Logger,
+) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearches: savedSearches,
+		notifications: notifications,
+		matcher:       matcher,
+		productRepo:   productRepo,
+		searchService: searchService,
+		logger:        logger,
+		pending:       make(map[string]string),
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Create guarda la búsqueda. Los productos que ya la cumplen no se
+// notifican: solo avisan los que aparezcan o cambien después. Devuelve un
+// *query.SyntaxError o ErrNothingToSearch si la búsqueda no es válida, y
+// port.ErrSavedSearchLimit si el usuario ya tiene MaxSavedSearchesPerUser.
+func (s *SavedSearchService) Create(ctx context.Context, userID, name, keyword string, filters model.SearchFilters) (*model.SavedSearch, error) {
+	criteria, err := s.searchService.Prepare(ctx, model.SearchCriteria{Keyword: keyword, Filters: filters})
+	if err != nil {
+		return nil, err
+	}
+
+	known, err := s.matcher.Matching(ctx, criteria, nil)
+	if err != nil {
+		s.logger.Error("Failed to match saved search", "error", err)
+		return nil, err
+	}
+
+	keyword = strings.TrimSpace(keyword)
+	if name = strings.TrimSpace(name); name == "" {
+		name = keyword
+	}
+
+	search := model.SavedSearch{
+		ID:            newID(),
+		UserID:        userID,
+		Name:          name,
+		Keyword:       keyword,
+		Filters:       filters,
+		CreatedAt:     time.Now(),
+		KnownProducts: known,
+	}
+	if err := s.savedSearches.Create(ctx, search, MaxSavedSearchesPerUser); err != nil {
+		if !errors.Is(err, port.ErrSavedSearchLimit) {
+			s.logger.Error("Failed to save search", "user_id", userID, "error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Search saved",
+		"user_id", userID,
+		"saved_search_id", search.ID,
+		"matching", len(known),
+	)
+	return &search, nil
+}
+
+func (s *SavedSearchService) List(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	searches, err := s.savedSearches.FindByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list saved searches", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return searches, nil
+}
+
+// Delete devuelve port.ErrSavedSearchNotFound si la búsqueda no existe o
+// es de otro usuario.
+func (s *SavedSearchService) Delete(ctx context.Context, userID, id string) error {
+	err := s.savedSearches.Delete(ctx, userID, id)
+	if err != nil && !errors.Is(err, port.ErrSavedSearchNotFound) {
+		s.logger.Error("Failed to delete saved search", "saved_search_id", id, "error", err)
+	}
+	return err
+}
+
+func (s *SavedSearchService) Notifications(ctx context.Context, userID string, limit int) ([]model.Notification, error) {
+	notifications, err := s.notifications.FindNotifications(ctx, userID, limit)
+	if err != nil {
+		s.logger.Error("Failed to list notifications", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// ProductsChanged encola productos para evaluar; no bloquea a quien
+// modificó el catálogo. Cambios seguidos del mismo producto se evalúan una
+// sola vez, y uno eliminado antes de evaluarse se descarta.
+func (s *SavedSearchService) ProductsChanged(changes []model.ProductChange) {
+	s.mu.Lock()
+	for _, c := range changes {
+		switch {
+		case c.Kind == model.ProductRemoved:
+			// A removed product can no longer match anything
+			delete(s.pending, c.ProductID)
+		case s.pending[c.ProductID] != model.ProductAdded:
+			// A product added and then updated before matching is still new
+			s.pending[c.ProductID] = c.Kind
+		}
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run evalúa los productos encolados hasta que ctx se cancele.
+func (s *SavedSearchService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+			s.matchPending(ctx)
+		}
+	}
+}
+
+func (s *SavedSearchService) matchPending(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]string)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+
+	searches, err := s.savedSearches.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load saved searches", "error", err)
+		return
+	}
+
+	var notifications []model.Notification
+	for _, search := range searches {
+		criteria, err := s.searchService.Prepare(ctx, model.SearchCriteria{Keyword: search.Keyword, Filters: search.Filters})
+		if err != nil {
+			s.logger.Warn("Skipping saved search", "saved_search_id", search.ID, "error", err)
+			continue
+		}
+
+		matching, err := s.matcher.Matching(ctx, criteria, ids)
+		if err != nil {
+			s.logger.Error("Failed to match saved search", "saved_search_id", search.ID, "error", err)
+			continue
+		}
+
+		for _, id := range matching {
+			if search.Knows(id) {
+				continue
+			}
+			product, err := s.productRepo.FindByID(ctx, id)
+			if err != nil {
+				continue
+			}
+			notifications = append(notifications, model.Notification{
+				ID:              newID(),
+				UserID:          search.UserID,
+				SavedSearchID:   search.ID,
+				SavedSearchName: search.Name,
+				ProductID:       product.ID,
+				Title:           product.Title,
+				Price:           product.Price,
+				Reason:          pending[id],
+				CreatedAt:       time.Now(),
+			})
+		}
+	}
+
+	added, err := s.notifications.Notify(ctx, notifications)
+	if err != nil {
+		s.logger.Error("Failed to record notifications", "error", err)
+		return
+	}
+
+	s.logger.Info("Saved searches evaluated",
+		"products", len(ids),
+		"saved_searches", len(searches),
+		"notifications", added,
+	)
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
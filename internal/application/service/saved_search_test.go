@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"meli-product-api/internal/domain/query"
+	"slices"
+	"testing"
+)
+
+// memSavedSearches implementa en memoria los repositorios de búsquedas
+// guardadas y notificaciones.
+type memSavedSearches struct {
+	searches      []model.SavedSearch
+	notifications []model.Notification
+}
+
+func (m *memSavedSearches) Create(ctx context.Context, search model.SavedSearch, maxPerUser int) error {
+	if len(m.searches) >= maxPerUser {
+		return port.ErrSavedSearchLimit
+	}
+	m.searches = append(m.searches, search)
+	return nil
+}
+
+func (m *memSavedSearches) FindByUser(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	return m.searches, nil
+}
+
+func (m *memSavedSearches) FindAll(ctx context.Context) ([]model.SavedSearch, error) {
+	return m.searches, nil
+}
+
+func (m *memSavedSearches) Delete(ctx context.Context, userID, id string) error {
+	return nil
+}
+
+func (m *memSavedSearches) Notify(ctx context.Context, notifications []model.Notification) (int, error) {
+	for _, n := range notifications {
+		i := slices.IndexFunc(m.searches, func(s model.SavedSearch) bool { return s.ID == n.SavedSearchID })
+		m.searches[i].NotifiedProducts = append(m.searches[i].NotifiedProducts, n.ProductID)
+		m.notifications = append(m.notifications, n)
+	}
+	return len(notifications), nil
+}
+
+func (m *memSavedSearches) FindNotifications(ctx context.Context, userID string, limit int) ([]model.Notification, error) {
+	return m.notifications, nil
+}
+
+// fakeCatalog responde Matching según la palabra buscada y FindByID con
+// los productos cargados.
+type fakeCatalog struct {
+	port.ProductRepository
+	products map[string]model.Product
+	matches  map[string][]string // término analizado -> productos
+}
+
+func (c *fakeCatalog) FindByID(ctx context.Context, id string) (*model.Product, error) {
+	p, ok := c.products[id]
+	if !ok {
+		return nil, errors.New("product not found")
+	}
+	return &p, nil
+}
+
+func (c *fakeCatalog) Matching(ctx context.Context, criteria model.SearchCriteria, ids []string) ([]string, error) {
+	var matching []string
+	for _, id := range c.matches[query.PositiveTerms(criteria.Query)[0]] {
+		if ids == nil || slices.Contains(ids, id) {
+			matching = append(matching, id)
+		}
+	}
+	return matching, nil
+}
+
+func TestSavedSearchServiceProductsChanged(t *testing.T) {
+	added := func(id string) model.ProductChange {
+		return model.ProductChange{ProductID: id, Kind: model.ProductAdded}
+	}
+	updated := func(id string) model.ProductChange {
+		return model.ProductChange{ProductID: id, Kind: model.ProductUpdated}
+	}
+	removed := func(id string) model.ProductChange {
+		return model.ProductChange{ProductID: id, Kind: model.ProductRemoved}
+	}
+
+	tests := []struct {
+		name    string
+		changes []model.ProductChange
+		want    map[string]string
+	}{
+		{name: "added", changes: []model.ProductChange{added("a")}, want: map[string]string{"a": model.ProductAdded}},
+		{name: "added then updated is still new", changes: []model.ProductChange{added("a"), updated("a")}, want: map[string]string{"a": model.ProductAdded}},
+		{name: "updated", changes: []model.ProductChange{updated("a"), updated("a")}, want: map[string]string{"a": model.ProductUpdated}},
+		{name: "removed before matching is dropped", changes: []model.ProductChange{added("a"), updated("b"), removed("a")}, want: map[string]string{"b": model.ProductUpdated}},
+		{name: "removed then added again", changes: []model.ProductChange{updated("a"), removed("a"), added("a")}, want: map[string]string{"a": model.ProductAdded}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSavedSearchService(nil, nil, nil, nil, nil, nil)
+			for _, c := range tt.changes {
+				s.ProductsChanged([]model.ProductChange{c})
+			}
+
+			if !maps.Equal(s.pending, tt.want) {
+				t.Errorf("pending = %v, want %v", s.pending, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavedSearchServiceMatching(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	catalog := &fakeCatalog{
+		products: map[string]model.Product{
+			"MLA1": {ID: "MLA1", Title: "Notebook Lenovo"},
+			"MLA2": {ID: "MLA2", Title: "Notebook HP"},
+			"MLA3": {ID: "MLA3", Title: "Notebook Asus"},
+		},
+		matches: map[string][]string{"notebook": {"MLA1"}},
+	}
+	repo := &memSavedSearches{}
+	searchService := NewProductSearchService(catalog, nil, nil, nil, nil, analysis.NewAnalyzer(), SearchOptions{}, logger)
+	s := NewSavedSearchService(repo, repo, catalog, catalog, searchService, logger)
+
+	search, err := s.Create(ctx, "u1", "", "notebook", model.SearchFilters{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(search.KnownProducts, []string{"MLA1"}) || search.Name != "notebook" {
+		t.Fatalf("Create() = %+v, want MLA1 known and the query as name", search)
+	}
+
+	catalog.matches["notebook"] = []string{"MLA1", "MLA2", "MLA3"}
+	rounds := []struct {
+		changes []model.ProductChange
+		want    []string // productID:reason notificados en la ronda
+	}{
+		{
+			changes: []model.ProductChange{
+				{ProductID: "MLA1", Kind: model.ProductUpdated},
+				{ProductID: "MLA2", Kind: model.ProductAdded},
+			},
+			want: []string{"MLA2:added"},
+		},
+		{
+			changes: []model.ProductChange{
+				{ProductID: "MLA2", Kind: model.ProductUpdated},
+				{ProductID: "MLA3", Kind: model.ProductUpdated},
+			},
+			want: []string{"MLA3:updated"},
+		},
+	}
+
+	for i, round := range rounds {
+		before := len(repo.notifications)
+		s.ProductsChanged(round.changes)
+		s.matchPending(ctx)
+
+		var got []string
+		for _, n := range repo.notifications[before:] {
+			got = append(got, n.ProductID+":"+n.Reason)
+		}
+		if !slices.Equal(got, round.want) {
+			t.Errorf("round %d notified %v, want %v", i, got, round.want)
+		}
+	}
+}
+
+func TestSavedSearchServiceCreateErrors(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	catalog := &fakeCatalog{matches: map[string][]string{}}
+	searchService := NewProductSearchService(catalog, nil, nil, nil, nil, analysis.NewAnalyzer(), SearchOptions{}, logger)
+
+	full := &memSavedSearches{searches: make([]model.SavedSearch, MaxSavedSearchesPerUser)}
+
+	tests := []struct {
+		name    string
+		repo    *memSavedSearches
+		keyword string
+		check   func(error) bool
+	}{
+		{name: "invalid query", repo: &memSavedSearches{}, keyword: "-usado", check: func(err error) bool {
+			var syntaxErr *query.SyntaxError
+			return errors.As(err, &syntaxErr)
+		}},
+		{name: "nothing to search", repo: &memSavedSearches{}, keyword: "de la", check: func(err error) bool {
+			return errors.Is(err, ErrNothingToSearch)
+		}},
+		{name: "limit reached", repo: full, keyword: "notebook", check: func(err error) bool {
+			return errors.Is(err, port.ErrSavedSearchLimit)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSavedSearchService(tt.repo, tt.repo, catalog, catalog, searchService, logger)
+			if _, err := s.Create(ctx, "u1", "", tt.keyword, model.SearchFilters{}); !tt.check(err) {
+				t.Errorf("Create() err = %v", err)
+			}
+		})
+	}
+}
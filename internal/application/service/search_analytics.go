@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"time"
+)
+
+// SearchAnalyticsService expone los reportes de búsquedas para el panel de
+// administración.
+type SearchAnalyticsService struct {
+	analytics port.SearchAnalytics
+	logger    *slog.Logger
+}
+
+func NewSearchAnalyticsService(
+	analytics port.SearchAnalytics,
+	logger *slog.Logger,
+) *SearchAnalyticsService {
+	return &SearchAnalyticsService{
+		analytics: analytics,
+		logger:    logger,
+	}
+}
+
+func (s *SearchAnalyticsService) TopQueries(ctx context.Context, window time.Duration, limit int) ([]model.QueryStats, error) {
+	stats, err := s.analytics.TopQueries(ctx, window, limit)
+	if err != nil {
+		s.logger.Error("Failed to get top queries", "window", window, "error", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *SearchAnalyticsService) ZeroResultQueries(ctx context.Context, window time.Duration, limit int) ([]model.QueryStats, error) {
+	stats, err := s.analytics.ZeroResultQueries(ctx, window, limit)
+	if err != nil {
+		s.logger.Error("Failed to get zero-result queries", "window", window, "error", err)
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *SearchAnalyticsService) SlowestSearches(ctx context.Context, window time.Duration, limit int) ([]model.SearchEvent, error) {
+	events, err := s.analytics.SlowestSearches(ctx, window, limit)
+	if err != nil {
+		s.logger.Error("Failed to get slowest searches", "window", window, "error", err)
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"time"
+)
+
+type SellerService struct {
+	sellerClient port.SellerClient
+	productRepo  port.ProductRepository
+	logger       *slog.Logger
+}
+
+func NewSellerService(
+	sellerClient port.SellerClient,
+	productRepo port.ProductRepository,
+	logger *slog.Logger,
+) *SellerService {
+	return &SellerService{
+		sellerClient: sellerClient,
+		productRepo:  productRepo,
+		logger:       logger,
+	}
+}
+
+// GetSeller devuelve port.ErrSellerNotFound si el vendedor no existe, o
+// port.ErrCircuitOpen si el servicio de vendedores viene fallando.
+func (s *SellerService) GetSeller(ctx context.Context, sellerID string) (*model.Seller, error) {
+	seller, err := s.sellerClient.GetByID(ctx, sellerID)
+	if err != nil {
+		if !errors.Is(err, port.ErrSellerNotFound) && !errors.Is(err, port.ErrCircuitOpen) {
+			s.logger.Error("Seller fetch failed", "seller_id", sellerID, "error", err)
+		}
+		return nil, err
+	}
+	return seller, nil
+}
+
+// ListProducts pagina las publicaciones del vendedor en el orden pedido;
+// como no hay query, relevance ordena por más vendidos. Devuelve
+// port.ErrSellerNotFound si el vendedor no existe.
+func (s *SellerService) ListProducts(ctx context.Context, sellerID string, sort model.SortOrder, page model.Page) (*model.SellerProducts, error) {
+	start := time.Now()
+
+	if _, err := s.GetSeller(ctx, sellerID); err != nil {
+		return nil, err
+	}
+
+	if sort == "" || sort == model.SortRelevance {
+		sort = model.SortBestSelling
+	}
+	criteria := model.SearchCriteria{
+		Filters: model.SearchFilters{Seller: sellerID},
+		Sort:    sort,
+	}
+
+	found, err := s.productRepo.Search(ctx, criteria, page)
+	if err != nil {
+		s.logger.Error("Seller products search failed", "seller_id", sellerID, "error", err)
+		return nil, err
+	}
+
+	products := make([]model.Product, len(found.Hits))
+	for i, hit := range found.Hits {
+		products[i] = hit.Product
+	}
+
+	s.logger.Info("Seller products listed",
+		"seller_id", sellerID,
+		"sort", sort,
+		"results", len(products),
+		"total", found.Total,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+
+	return &model.SellerProducts{Products: products, Total: found.Total, Sort: sort}, nil
+}
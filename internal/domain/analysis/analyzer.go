@@ -0,0 +1,113 @@
+package analysis
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token es un término junto con su ubicación en el texto original.
+// Start y End son offsets en bytes sobre el texto sin normalizar, así el
+// término puede mapearse de vuelta a los caracteres originales aunque se
+// hayan plegado tildes o aplicado stemming. Position es el índice de la
+// palabra en el texto e incluye a las stopwords descartadas, para que las
+// distancias entre términos se conserven.
+type Token struct {
+	Term     string
+	Position int
+	Start    int
+	End      int
+}
+
+// Analyzer convierte texto libre en términos indexables. Se usa tanto al
+// indexar productos como al interpretar la query del usuario, para que
+// ambos lados produzcan exactamente los mismos términos.
+type Analyzer struct {
+	stopwords map[string]bool
+	stemming  bool
+}
+
+// NewAnalyzer devuelve el analizador para español: plegado de tildes,
+// stopwords y stemming liviano.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{
+		stopwords: spanishStopwords,
+		stemming:  true,
+	}
+}
+
+// Tokenize separa el texto en palabras (secuencias de letras y dígitos) y
+// las normaliza con Fold, sin descartar stopwords ni aplicar stemming.
+func (a *Analyzer) Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		tokens = append(tokens, Token{
+			Term:     Fold(text[start:end]),
+			Position: len(tokens),
+			Start:    start,
+			End:      end,
+		})
+		start = -1
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks belong to the preceding letter
+		default:
+			flush(i)
+		}
+		i += size
+	}
+	flush(len(text))
+
+	return tokens
+}
+
+// Analyze aplica Tokenize, descarta stopwords y reduce cada palabra a su
+// raíz.
+func (a *Analyzer) Analyze(text string) []Token {
+	tokens := a.Tokenize(text)
+
+	out := tokens[:0]
+	for _, tok := range tokens {
+		if a.IsStopword(tok.Term) {
+			continue
+		}
+		tok.Term = a.Normalize(tok.Term)
+		out = append(out, tok)
+	}
+
+	return out
+}
+
+func (a *Analyzer) IsStopword(term string) bool {
+	return a.stopwords[term]
+}
+
+// Normalize reduce un término ya plegado a la forma que guarda el índice.
+func (a *Analyzer) Normalize(term string) string {
+	if a.stemming {
+		return stem(term)
+	}
+	return term
+}
+
+// Terms devuelve solo los términos de Analyze.
+func (a *Analyzer) Terms(text string) []string {
+	tokens := a.Analyze(text)
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = t.Term
+	}
+	return terms
+}
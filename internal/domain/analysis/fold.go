@@ -0,0 +1,39 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldTable cubre las formas precompuestas habituales en español y
+// portugués. Las formas descompuestas (letra + marca combinante) se
+// resuelven descartando la marca.
+var foldTable = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ñ': 'n', 'ç': 'c', 'ý': 'y', 'ÿ': 'y',
+}
+
+// Fold pasa el texto a minúsculas y elimina diacríticos, de modo que
+// "Teléfonos", "telefonos" y "TELÉFONOS" quedan iguales.
+func Fold(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for _, r := range text {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		r = unicode.ToLower(r)
+		if folded, ok := foldTable[r]; ok {
+			r = folded
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span ubica un fragmento del texto original, en bytes.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Highlight devuelve los fragmentos de text cuyos términos analizados están
+// en terms. Como los offsets vienen de Tokenize, "Teléfonos" se marca
+// completo aunque el término buscado sea "telefon". Fragmentos separados
+// solo por espacios se unen en uno.
+func (a *Analyzer) Highlight(text string, terms map[string]bool) []Span {
+	var spans []Span
+	for _, tok := range a.Analyze(text) {
+		if !terms[tok.Term] {
+			continue
+		}
+		if n := len(spans); n > 0 && strings.TrimSpace(text[spans[n-1].End:tok.Start]) == "" {
+			spans[n-1].End = tok.End
+			continue
+		}
+		spans = append(spans, Span{Start: tok.Start, End: tok.End})
+	}
+	return spans
+}
+
+// Snippet recorta text a una ventana de hasta maxRunes caracteres que
+// empieza poco antes del primer fragmento marcado, cortando en límites de
+// palabra y agregando "…" donde se recortó. Devuelve los fragmentos
+// reubicados dentro del recorte.
+func Snippet(text string, spans []Span, maxRunes int) (string, []Span) {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text, spans
+	}
+
+	start := 0
+	if len(spans) > 0 {
+		// Leave some context before the first match
+		start = backRunes(text, spans[0].Start, maxRunes/4)
+		start = wordStart(text, start)
+	}
+
+	end := forwardRunes(text, start, maxRunes)
+	end = wordEnd(text, start, end)
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(text) {
+		suffix = "…"
+	}
+
+	window := strings.TrimLeftFunc(text[start:end], unicode.IsSpace)
+	base := end - len(window)
+	window = strings.TrimRightFunc(window, unicode.IsSpace)
+
+	// Shift spans from text offsets to snippet offsets
+	shift := len(prefix) - base
+	var out []Span
+	for _, s := range spans {
+		if s.Start >= base && s.End <= base+len(window) {
+			out = append(out, Span{Start: s.Start + shift, End: s.End + shift})
+		}
+	}
+
+	return prefix + window + suffix, out
+}
+
+func backRunes(text string, from, n int) int {
+	for n > 0 && from > 0 {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+		n--
+	}
+	return from
+}
+
+func forwardRunes(text string, from, n int) int {
+	for n > 0 && from < len(text) {
+		_, size := utf8.DecodeRuneInString(text[from:])
+		from += size
+		n--
+	}
+	return from
+}
+
+// wordStart mueve i hacia adelante hasta el comienzo de una palabra si cae
+// en medio de una.
+func wordStart(text string, i int) int {
+	if i == 0 {
+		return 0
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	if !isWordRune(prev) {
+		return i
+	}
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isWordRune(r) {
+			return i
+		}
+		i += size
+	}
+	return i
+}
+
+// wordEnd retrocede end hasta el final de la última palabra completa, sin
+// pasar de start.
+func wordEnd(text string, start, end int) int {
+	if end >= len(text) {
+		return len(text)
+	}
+	next, _ := utf8.DecodeRuneInString(text[end:])
+	if !isWordRune(next) {
+		return end
+	}
+	for i := end; i > start; {
+		r, size := utf8.DecodeLastRuneInString(text[:i])
+		if !isWordRune(r) {
+			return i
+		}
+		i -= size
+	}
+	return end
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	a := NewAnalyzer()
+
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  []string // fragmentos marcados del texto original
+	}{
+		{name: "stemmed match keeps the original word", text: "Teléfonos Samsung", query: "telefono", want: []string{"Teléfonos"}},
+		{name: "accents and case", text: "CÁMARA de fotos", query: "camara", want: []string{"CÁMARA"}},
+		{name: "adjacent matches merge", text: "Apple iPhone 15 Pro", query: "iphone apple", want: []string{"Apple iPhone"}},
+		{name: "separate matches stay apart", text: "iPhone con funda para iPhone", query: "iphone", want: []string{"iPhone", "iPhone"}},
+		{name: "multibyte offsets", text: "Año nuevo: cañón de agua", query: "cañon", want: []string{"cañón"}},
+		{name: "no match", text: "Notebook Lenovo", query: "samsung"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := make(map[string]bool)
+			for _, term := range a.Terms(tt.query) {
+				terms[term] = true
+			}
+
+			var got []string
+			for _, s := range a.Highlight(tt.text, terms) {
+				got = append(got, tt.text[s.Start:s.End])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := "Este producto viene con garantía oficial de un año y envío gratis a todo el país. " +
+		"La batería dura dos días con uso moderado y la cámara principal graba en 4K."
+
+	tests := []struct {
+		name     string
+		text     string
+		span     string // fragmento marcado, vacío si no hay
+		maxRunes int
+		want     string
+	}{
+		{name: "short text is kept", text: "Batería de larga duración", span: "Batería", maxRunes: 50, want: "Batería de larga duración"},
+		{name: "no spans starts at the beginning", text: long, maxRunes: 30, want: "Este producto viene con…"},
+		{name: "window around the match", text: long, span: "cámara", maxRunes: 40, want: "…y la cámara principal graba en 4K."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spans []Span
+			if tt.span != "" {
+				start := strings.Index(tt.text, tt.span)
+				spans = []Span{{Start: start, End: start + len(tt.span)}}
+			}
+
+			got, gotSpans := Snippet(tt.text, spans, tt.maxRunes)
+			if got != tt.want {
+				t.Fatalf("Snippet() = %q, want %q", got, tt.want)
+			}
+			if len(gotSpans) != len(spans) {
+				t.Fatalf("Snippet() spans = %v, want %d", gotSpans, len(spans))
+			}
+			for _, s := range gotSpans {
+				if got[s.Start:s.End] != tt.span {
+					t.Errorf("span %v marks %q, want %q", s, got[s.Start:s.End], tt.span)
+				}
+			}
+		})
+	}
+}
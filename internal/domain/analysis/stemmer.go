@@ -0,0 +1,51 @@
+package analysis
+
+import "strings"
+
+// stem es un stemmer liviano para español: quita plurales y la vocal final
+// de género, suficiente para que "teléfonos" y "teléfono" o "notebooks" y
+// "notebook" coincidan sin los falsos positivos de un stemmer agresivo.
+// Singular y plural terminan siempre en el mismo stem ("luz" y "luces" en
+// "luc", "interés" e "intereses" en "inter"). Espera un término ya
+// normalizado con Fold.
+func stem(term string) string {
+	if !isAlpha(term) {
+		return term
+	}
+
+	n := len(term)
+	switch {
+	case strings.HasSuffix(term, "z"):
+		// "luz" -> "luc", like "luces"
+		return term[:n-1] + "c"
+	case strings.HasSuffix(term, "eses") && n >= 8:
+		// "intereses" -> "inter", like "interés"
+		return term[:n-4]
+	case strings.HasSuffix(term, "es") && n >= 5 && (!isVowel(term[n-3]) || term[n-3] == 'i'):
+		// "motores" -> "motor", "luces" -> "luc", "países" -> "pais"
+		return term[:n-2]
+	case strings.HasSuffix(term, "s") && n >= 4 && term[n-2] != 'i' && term[n-2] != 'u':
+		// "teléfonos" -> "telefono" and English loanwords: "notebooks"
+		// -> "notebook". Words in -is/-us are singular ("país", "virus")
+		term = term[:n-1]
+	}
+
+	// Gender vowel: "telefono" -> "telefon", "dulce" -> "dulc"
+	if n := len(term); n >= 4 && strings.IndexByte("aeo", term[n-1]) >= 0 {
+		return term[:n-1]
+	}
+	return term
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+func isAlpha(term string) bool {
+	for i := 0; i < len(term); i++ {
+		if term[i] < 'a' || term[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
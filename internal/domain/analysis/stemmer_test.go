@@ -0,0 +1,49 @@
+package analysis
+
+import "testing"
+
+func TestStemPluralAndSingularMatch(t *testing.T) {
+	tests := []struct {
+		plural, singular string
+		want             string
+	}{
+		{"telefonos", "telefono", "telefon"},
+		{"notebooks", "notebook", "notebook"},
+		{"casas", "casa", "cas"},
+		{"cables", "cable", "cabl"},
+		{"clases", "clase", "clas"},
+		{"clientes", "cliente", "client"},
+		{"motores", "motor", "motor"},
+		{"auriculares", "auricular", "auricular"},
+		{"dulces", "dulce", "dulc"},
+		{"luces", "luz", "luc"},
+		{"lapices", "lapiz", "lapic"},
+		{"intereses", "interes", "inter"},
+		{"ingleses", "ingles", "ingl"},
+		{"meses", "mes", "mes"},
+		{"paises", "pais", "pais"},
+		{"series", "serie", "seri"},
+		{"motos", "moto", "mot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.plural, func(t *testing.T) {
+			if got := stem(tt.plural); got != tt.want {
+				t.Errorf("stem(%q) = %q, want %q", tt.plural, got, tt.want)
+			}
+			if got := stem(tt.singular); got != tt.want {
+				t.Errorf("stem(%q) = %q, want %q", tt.singular, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStemLeavesShortAndNonAlphaTerms(t *testing.T) {
+	tests := []string{"tv", "sol", "usb", "mes", "virus", "4k", "ps5", "128gb", "wi-fi"}
+
+	for _, term := range tests {
+		if got := stem(term); got != term {
+			t.Errorf("stem(%q) = %q, want it unchanged", term, got)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package analysis
+
+// spanishStopwords son palabras muy frecuentes que no aportan a la búsqueda.
+// Están en su forma normalizada (sin tildes).
+var spanishStopwords = toSet(
+	"a", "al", "algo", "ante", "con", "como", "contra", "cual", "de", "del",
+	"desde", "donde", "durante", "e", "el", "ella", "ellos", "en", "entre",
+	"es", "esa", "ese", "eso", "esta", "estas", "este", "esto", "estos",
+	"ha", "hay", "la", "las", "le", "les", "lo", "los", "mas", "me", "mi",
+	"mucho", "muy", "ni", "nos", "o", "otra", "otro", "para", "pero", "por",
+	"porque", "que", "quien", "se", "si", "sobre", "son", "su", "sus",
+	"tambien", "te", "tu", "u", "un", "una", "uno", "unos", "unas", "y", "ya",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+type CircuitState string
+
+const (
+	// CircuitClosed deja pasar las llamadas y mide su tasa de fallas.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rechaza las llamadas sin hacerlas hasta que pase el
+	// tiempo de espera.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen deja pasar unas pocas llamadas de prueba para decidir
+	// si vuelve a cerrarse.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitStatus es el estado de un circuit breaker en un momento dado;
+// Requests y Failures cuentan la ventana vigente.
+type CircuitStatus struct {
+	Name        string
+	State       CircuitState
+	Since       time.Time
+	Requests    int
+	Failures    int
+	FailureRate float64
+}
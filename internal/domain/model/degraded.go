@@ -0,0 +1,18 @@
+package model
+
+// Motivos por los que una sección se devuelve sin datos (o con los de
+// respaldo).
+const (
+	DegradedTimeout  = "timeout"
+	DegradedNotFound = "not_found"
+	// DegradedCircuitOpen: el servicio viene fallando y no se lo llamó.
+	DegradedCircuitOpen = "circuit_open"
+	DegradedError       = "error"
+)
+
+// DegradedSection informa una sección del detalle que no se pudo
+// completar.
+type DegradedSection struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"`
+}
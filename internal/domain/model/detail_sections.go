@@ -0,0 +1,24 @@
+package model
+
+// Secciones del detalle de producto que dependen de otros servicios.
+const (
+	SectionSeller    = "seller"
+	SectionReviews   = "reviews"
+	SectionQuestions = "questions"
+	SectionRelated   = "related"
+)
+
+var DetailSectionNames = []string{
+	SectionSeller,
+	SectionReviews,
+	SectionQuestions,
+	SectionRelated,
+}
+
+// DetailSections indica qué secciones del detalle se piden; nil pide
+// todas. Las que no se piden no se consultan.
+type DetailSections map[string]bool
+
+func (s DetailSections) Has(section string) bool {
+	return s == nil || s[section]
+}
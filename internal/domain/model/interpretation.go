@@ -0,0 +1,27 @@
+package model
+
+// Tipos de filtro que la búsqueda puede deducir del texto libre.
+const (
+	InterpretPrice     = "price"
+	InterpretCondition = "condition"
+	InterpretBrand     = "brand"
+)
+
+// InterpretKinds lista los tipos de interpretación válidos.
+var InterpretKinds = []string{InterpretPrice, InterpretCondition, InterpretBrand}
+
+// Interpretation describe cómo se entendió una query en lenguaje natural:
+// los filtros deducidos del texto y la query que quedó para buscar.
+type Interpretation struct {
+	Keyword string
+	Filters []InterpretedFilter
+}
+
+// InterpretedFilter es un filtro deducido de Text ("menos de 500000"). Param
+// y Value son el parámetro de búsqueda equivalente (price_max=500000).
+type InterpretedFilter struct {
+	Kind  string
+	Param string
+	Value string
+	Text  string
+}
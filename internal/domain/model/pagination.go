@@ -0,0 +1,53 @@
+package model
+
+import "errors"
+
+// ErrCursorExpired indica un cursor de relevancia emitido antes de un
+// cambio en el catálogo: los puntajes ya no son comparables.
+var ErrCursorExpired = errors.New("cursor expired: the catalog changed since it was issued")
+
+// Page indica qué porción de los resultados se pide. Con After se
+// continúa a partir de un cursor y Offset se ignora.
+type Page struct {
+	Limit  int
+	Offset int
+	After  *SearchCursor
+}
+
+// SearchCursor es la posición de un resultado dentro de un orden: el
+// valor por el que se ordena y el ID que desempata. A diferencia del
+// offset, sigue siendo válida si el catálogo cambia entre páginas, salvo
+// en relevancia: el puntaje BM25 depende de todo el catálogo, así que el
+// cursor queda atado a la Generation en la que se calculó.
+type SearchCursor struct {
+	Sort       SortOrder
+	Fuzzy      bool
+	Key        float64
+	ID         string
+	Generation uint64
+}
+
+// CursorAt devuelve la posición de hit en el orden o.
+func (o SortOrder) CursorAt(hit SearchHit) SearchCursor {
+	cursor := SearchCursor{
+		Sort:  o,
+		Fuzzy: hit.Fuzzy,
+		Key:   o.sortKey(hit),
+		ID:    hit.Product.ID,
+	}
+	if o == SortRelevance {
+		cursor.Generation = hit.Generation
+	}
+	return cursor
+}
+
+// Expired reporta si el cursor ya no sirve para un catálogo en generation.
+func (c SearchCursor) Expired(generation uint64) bool {
+	return c.Sort == SortRelevance && c.Generation != generation
+}
+
+// Precedes reporta si hit va después del cursor, es decir, si corresponde
+// a una página siguiente.
+func (c SearchCursor) Precedes(hit SearchHit) bool {
+	return c.Sort.compare(c.Fuzzy, c.Key, c.ID, hit.Fuzzy, c.Sort.sortKey(hit), hit.Product.ID) < 0
+}
@@ -0,0 +1,33 @@
+package model
+
+import "errors"
+
+// MaxHistogramBuckets acota el tamaño del histograma de precios.
+const MaxHistogramBuckets = 100
+
+// ErrHistogramTooFine indica un Interval que partiría el rango de precios
+// en más de MaxHistogramBuckets intervalos.
+var ErrHistogramTooFine = errors.New("price histogram interval is too small for the price range")
+
+// HistogramSpec indica cómo partir el rango de precios: en Buckets
+// intervalos de ancho redondeado (adaptado al rango de los resultados) o,
+// si Interval > 0, en intervalos de ese ancho fijo.
+type HistogramSpec struct {
+	Buckets  int
+	Interval float64
+}
+
+// PriceHistogram es la distribución de precios de todas las coincidencias.
+// Los intervalos son contiguos, cubren [Min, Max] e incluyen los vacíos.
+type PriceHistogram struct {
+	Min     float64
+	Max     float64
+	Buckets []HistogramBucket
+}
+
+// HistogramBucket cubre [Min, Max).
+type HistogramBucket struct {
+	Min   float64
+	Max   float64
+	Count int
+}
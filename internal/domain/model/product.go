@@ -17,6 +17,7 @@ type Product struct {
 	Attributes        []Attribute `json:"attributes"`
 	Brand             string      `json:"brand"`
 	Model             string      `json:"model"`
+	SellerID          string      `json:"seller_id"`
 	CreatedAt         time.Time   `json:"created_at"`
 	UpdatedAt         time.Time   `json:"updated_at"`
 }
@@ -35,4 +36,23 @@ type ProductDetails struct {
 	TotalReviews    int        `json:"total_reviews"`
 	Questions       []Question `json:"questions"`
 	RelatedProducts []Product  `json:"related_products"`
+	// Degraded lista las secciones que fallaron o vencieron; el resto del
+	// detalle igual se devuelve.
+	Degraded []DegradedSection `json:"degraded_sections,omitempty"`
+}
+
+func (p Product) HasFreeShipping() bool {
+	return p.Price > FreeShippingThreshold
+}
+
+// Discount devuelve el porcentaje de descuento, derivándolo del precio
+// original cuando el dato no viene explícito.
+func (p Product) Discount() int {
+	if p.DiscountPercent != nil {
+		return *p.DiscountPercent
+	}
+	if p.OriginalPrice != nil && *p.OriginalPrice > p.Price {
+		return int((*p.OriginalPrice - p.Price) / *p.OriginalPrice * 100)
+	}
+	return 0
 }
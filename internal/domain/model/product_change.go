@@ -0,0 +1,14 @@
+package model
+
+// Tipos de cambio en el catálogo.
+const (
+	ProductAdded   = "added"
+	ProductUpdated = "updated"
+	ProductRemoved = "removed"
+)
+
+// ProductChange informa que un producto se agregó, modificó o eliminó.
+type ProductChange struct {
+	ProductID string
+	Kind      string
+}
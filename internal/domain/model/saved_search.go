@@ -0,0 +1,43 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// SavedSearch es una búsqueda que un usuario guarda para que se le avise
+// cuando aparezcan productos nuevos que la cumplan.
+type SavedSearch struct {
+	ID        string        `json:"id"`
+	UserID    string        `json:"user_id"`
+	Name      string        `json:"name"`
+	Keyword   string        `json:"query"`
+	Filters   SearchFilters `json:"filters"`
+	CreatedAt time.Time     `json:"created_at"`
+	// KnownProducts son los productos que ya cumplían la búsqueda al
+	// guardarla; nunca se notifican.
+	KnownProducts []string `json:"known_products"`
+	// NotifiedProducts son los productos ya notificados; cada producto se
+	// avisa una sola vez. El repositorio puede acotarla olvidando los más
+	// viejos.
+	NotifiedProducts []string `json:"notified_products,omitempty"`
+}
+
+// Knows reporta si productID ya cumplía la búsqueda al guardarla o ya fue
+// notificado.
+func (s SavedSearch) Knows(productID string) bool {
+	return slices.Contains(s.KnownProducts, productID) || slices.Contains(s.NotifiedProducts, productID)
+}
+
+// Notification avisa que un producto empezó a cumplir una búsqueda guardada.
+type Notification struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	SavedSearchID   string    `json:"saved_search_id"`
+	SavedSearchName string    `json:"saved_search_name"`
+	ProductID       string    `json:"product_id"`
+	Title           string    `json:"title"`
+	Price           float64   `json:"price"`
+	Reason          string    `json:"reason"`
+	CreatedAt       time.Time `json:"created_at"`
+}
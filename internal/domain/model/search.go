@@ -0,0 +1,326 @@
+package model
+
+import (
+	"cmp"
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/query"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type SortOrder string
+
+const (
+	SortRelevance   SortOrder = "relevance"
+	SortPriceAsc    SortOrder = "price_asc"
+	SortPriceDesc   SortOrder = "price_desc"
+	SortBestSelling SortOrder = "best_selling"
+	SortNewest      SortOrder = "newest"
+	SortDiscount    SortOrder = "biggest_discount"
+)
+
+var SortOrders = []SortOrder{
+	SortRelevance,
+	SortPriceAsc,
+	SortPriceDesc,
+	SortBestSelling,
+	SortNewest,
+	SortDiscount,
+}
+
+func (o SortOrder) IsValid() bool {
+	for _, valid := range SortOrders {
+		if o == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare ordena dos resultados según el criterio: devuelve un valor
+// negativo si a va antes que b. Los empates se desempatan por ID, así el
+// orden es total y estable entre páginas.
+func (o SortOrder) Compare(a, b SearchHit) int {
+	return o.compare(a.Fuzzy, o.sortKey(a), a.Product.ID, b.Fuzzy, o.sortKey(b), b.Product.ID)
+}
+
+// sortKey es el valor por el que o ordena el resultado.
+func (o SortOrder) sortKey(h SearchHit) float64 {
+	switch o {
+	case SortPriceAsc, SortPriceDesc:
+		return h.Product.Price
+	case SortBestSelling:
+		return float64(h.Product.SoldQuantity)
+	case SortNewest:
+		return float64(h.Product.CreatedAt.UnixMilli())
+	case SortDiscount:
+		return float64(h.Product.Discount())
+	}
+	return h.Score
+}
+
+func (o SortOrder) compare(aFuzzy bool, aKey float64, aID string, bFuzzy bool, bKey float64, bID string) int {
+	// Exact matches always rank above typo-tolerant ones
+	if o == SortRelevance && aFuzzy != bFuzzy {
+		if aFuzzy {
+			return 1
+		}
+		return -1
+	}
+
+	c := cmp.Compare(aKey, bKey)
+	if o != SortPriceAsc {
+		c = -c
+	}
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(aID, bID)
+}
+
+// GroupBy indica cómo colapsar resultados equivalentes.
+type GroupBy string
+
+const (
+	GroupNone GroupBy = ""
+	// GroupByModel junta las publicaciones de una misma marca y modelo.
+	GroupByModel GroupBy = "model"
+)
+
+// SearchCriteria describe qué productos busca el usuario; la paginación
+// viaja por separado.
+type SearchCriteria struct {
+	Keyword string
+	// Query es Keyword ya parseada y analizada; es lo que se evalúa contra
+	// el índice. nil es modo navegación: todo el catálogo que pase los
+	// filtros.
+	Query   query.Node
+	Filters SearchFilters
+	Sort    SortOrder
+	// Fuzzy habilita coincidencias con términos a pocos errores de
+	// tipeo de los buscados.
+	Fuzzy bool
+	// Highlight pide marcar en cada resultado dónde coincidió la query.
+	Highlight bool
+	// Facets pide, además de la página, los facets y el histograma de
+	// precios de todas las coincidencias.
+	Facets bool
+	// Histogram define los intervalos del histograma de precios.
+	Histogram HistogramSpec
+	// GroupBy colapsa los resultados; los facets y el histograma siguen
+	// contando publicaciones.
+	GroupBy GroupBy
+	// SkipInterpretation desactiva, por tipo (InterpretPrice...), la
+	// deducción de filtros a partir del texto de Keyword.
+	SkipInterpretation map[string]bool
+}
+
+// SearchFilters son filtros estructurados; los valores cero no filtran.
+type SearchFilters struct {
+	PriceMin     *float64 `json:"price_min,omitempty"`
+	PriceMax     *float64 `json:"price_max,omitempty"`
+	Condition    string   `json:"condition,omitempty"`
+	Brand        string   `json:"brand,omitempty"`
+	Category     string   `json:"category,omitempty"`
+	Seller       string   `json:"seller,omitempty"`
+	InStock      bool     `json:"in_stock,omitempty"`
+	FreeShipping *bool    `json:"free_shipping,omitempty"`
+	// Attributes exige, por cada nombre de atributo, alguno de los valores
+	// dados (attr[Color]=Negro).
+	Attributes map[string][]string `json:"attributes,omitempty"`
+}
+
+// Matches reporta si el producto cumple todos los filtros.
+func (f SearchFilters) Matches(p Product) bool {
+	if f.PriceMin != nil && p.Price < *f.PriceMin {
+		return false
+	}
+	if f.PriceMax != nil && p.Price > *f.PriceMax {
+		return false
+	}
+	if f.Condition != "" && !sameText(p.Condition, f.Condition) {
+		return false
+	}
+	if f.Brand != "" && !sameText(p.Brand, f.Brand) {
+		return false
+	}
+	if f.Category != "" && !sameText(p.Category, f.Category) {
+		return false
+	}
+	if f.Seller != "" && p.SellerID != f.Seller {
+		return false
+	}
+	if f.InStock && p.AvailableQuantity <= 0 {
+		return false
+	}
+	if f.FreeShipping != nil && p.HasFreeShipping() != *f.FreeShipping {
+		return false
+	}
+	for name, values := range f.Attributes {
+		if !hasAttribute(p, name, values) {
+			return false
+		}
+	}
+	return true
+}
+
+// Scoped reporta si los filtros acotan el catálogo a una sección
+// (categoría, marca o vendedor), lo que alcanza para navegar sin palabra
+// clave.
+func (f SearchFilters) Scoped() bool {
+	return f.Category != "" || f.Brand != "" || f.Seller != ""
+}
+
+func hasAttribute(p Product, name string, values []string) bool {
+	for _, attr := range p.Attributes {
+		if !sameText(attr.Name, name) {
+			continue
+		}
+		for _, v := range values {
+			if sameText(attr.Value, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String devuelve los filtros activos como parámetros ordenados
+// ("brand=Apple&price_max=500000"), útil para logs y analítica.
+func (f SearchFilters) String() string {
+	values := url.Values{}
+	if f.PriceMin != nil {
+		values.Set("price_min", strconv.FormatFloat(*f.PriceMin, 'f', -1, 64))
+	}
+	if f.PriceMax != nil {
+		values.Set("price_max", strconv.FormatFloat(*f.PriceMax, 'f', -1, 64))
+	}
+	if f.Condition != "" {
+		values.Set("condition", f.Condition)
+	}
+	if f.Brand != "" {
+		values.Set("brand", f.Brand)
+	}
+	if f.Category != "" {
+		values.Set("category", f.Category)
+	}
+	if f.Seller != "" {
+		values.Set("seller", f.Seller)
+	}
+	if f.InStock {
+		values.Set("in_stock", "true")
+	}
+	if f.FreeShipping != nil {
+		values.Set("free_shipping", strconv.FormatBool(*f.FreeShipping))
+	}
+	for name, vals := range f.Attributes {
+		values["attr["+name+"]"] = vals
+	}
+	return values.Encode()
+}
+
+// sameText compara sin distinguir mayúsculas ni tildes.
+func sameText(a, b string) bool {
+	return analysis.Fold(strings.TrimSpace(a)) == analysis.Fold(strings.TrimSpace(b))
+}
+
+// SearchResult agrupa una página de resultados con los datos calculados
+// sobre el conjunto completo de coincidencias.
+type SearchResult struct {
+	Hits []SearchHit
+	// Total cuenta publicaciones; con GroupBy, Groups cuenta grupos y es lo
+	// que se pagina.
+	Total  int
+	Groups int
+	Facets SearchFacets
+	// PriceHistogram es nil si no hubo resultados.
+	PriceHistogram *PriceHistogram
+	// Suggestion es la query corregida cuando la original no trajo
+	// resultados; AutoCorrected indica que Hits ya corresponde a ella.
+	Suggestion    string
+	AutoCorrected bool
+	// Next ubica el último resultado de la página; nil si no hay más.
+	Next *SearchCursor
+	// Interpretation son los filtros deducidos del texto; nil si no hubo.
+	Interpretation *Interpretation
+}
+
+// SearchPage es lo que devuelve el repositorio para una búsqueda: la página
+// pedida y lo que describe al conjunto completo, todo calculado sobre la
+// misma versión del catálogo.
+type SearchPage struct {
+	Hits []SearchHit
+	// Total cuenta publicaciones; Groups, grupos, solo con GroupBy.
+	Total  int
+	Groups int
+	// Facets y PriceHistogram solo se completan con SearchCriteria.Facets.
+	Facets         SearchFacets
+	PriceHistogram *PriceHistogram
+}
+
+// SearchHit es un producto encontrado junto con su puntaje de relevancia.
+type SearchHit struct {
+	Product Product
+	Score   float64
+	// Fuzzy indica que el producto solo coincide gracias a términos
+	// corregidos; estos resultados van después de los exactos.
+	Fuzzy bool
+	// Highlight se completa solo si SearchCriteria.Highlight.
+	Highlight *Highlight
+	// Group resume el grupo que el resultado representa; solo con GroupBy.
+	Group *HitGroup
+	// Generation es la versión del catálogo sobre la que se calculó Score.
+	Generation uint64
+}
+
+// HitGroup resume las variantes colapsadas en un resultado, incluido él.
+type HitGroup struct {
+	Variants int
+	MinPrice float64
+	MaxPrice float64
+}
+
+// Highlight ubica las coincidencias de la query en el título y en un
+// fragmento de la descripción.
+type Highlight struct {
+	Title   HighlightedText
+	Snippet HighlightedText
+}
+
+// HighlightedText es un texto junto con los fragmentos (offsets en bytes
+// sobre Text) que coinciden con la query.
+type HighlightedText struct {
+	Text    string
+	Matches []analysis.Span
+}
+
+type SearchFacets struct {
+	Categories   []FacetValue
+	Brands       []FacetValue
+	Conditions   []FacetValue
+	FreeShipping []FacetValue
+	PriceRanges  []PriceRangeFacet
+	// Attributes solo se calcula cuando todos los resultados son de una
+	// misma categoría; entre categorías distintas los atributos no son
+	// comparables.
+	Attributes []AttributeFacet
+}
+
+// AttributeFacet cuenta los valores de un atributo entre los resultados.
+type AttributeFacet struct {
+	Name   string
+	Values []FacetValue
+}
+
+type FacetValue struct {
+	Value string
+	Count int
+}
+
+// PriceRangeFacet cubre el rango [Min, Max); Max nil significa sin tope.
+type PriceRangeFacet struct {
+	Min   float64
+	Max   *float64
+	Count int
+}
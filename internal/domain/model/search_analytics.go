@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// SearchEvent es una búsqueda registrada para analítica.
+type SearchEvent struct {
+	// Query es la query normalizada (sin tildes, en minúsculas y con los
+	// espacios colapsados), así variantes triviales cuentan como una. Vacía
+	// en búsquedas de navegación, solo con filtros.
+	Query   string        `json:"query"`
+	Filters string        `json:"filters,omitempty"`
+	Results int           `json:"results"`
+	Latency time.Duration `json:"latency"`
+	At      time.Time     `json:"at"`
+}
+
+// QueryStats resume las búsquedas de una query dentro de una ventana.
+type QueryStats struct {
+	Query string
+	// Filters solo se completa en el reporte de queries sin resultados,
+	// donde los filtros suelen ser la causa.
+	Filters        string
+	Searches       int
+	ZeroResults    int
+	AvgLatency     time.Duration
+	LastSearchedAt time.Time
+}
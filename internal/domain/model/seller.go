@@ -9,3 +9,11 @@ type Seller struct {
 	YearsActive     int     `json:"years_active"`
 	IsOfficialStore bool    `json:"is_official_store"`
 }
+
+// SellerProducts es una página de las publicaciones de un vendedor; Total
+// cuenta todas y Sort es el orden efectivamente aplicado.
+type SellerProducts struct {
+	Products []Product
+	Total    int
+	Sort     SortOrder
+}
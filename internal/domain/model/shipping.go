@@ -8,3 +8,6 @@ type Shipping struct {
 	FullFulfillment   bool    `json:"full_fulfillment"`
 	PickupAvailable   string  `json:"pickup_available"`
 }
+
+// FreeShippingThreshold es el precio a partir del cual el envío es gratis.
+const FreeShippingThreshold = 50000.0
@@ -0,0 +1,15 @@
+package model
+
+// Tipos de sugerencia de autocompletado
+const (
+	SuggestionTitle    = "title"
+	SuggestionBrand    = "brand"
+	SuggestionModel    = "model"
+	SuggestionCategory = "category"
+)
+
+type Suggestion struct {
+	Text   string `json:"text"`
+	Kind   string `json:"kind"`
+	Weight int    `json:"weight"`
+}
@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+// BrandCatalog conoce las marcas del catálogo, para reconocerlas en el
+// texto de una búsqueda.
+type BrandCatalog interface {
+	Brands(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,7 @@
+package port
+
+import "errors"
+
+// ErrCircuitOpen indica que la llamada a otro servicio no se hizo porque
+// viene fallando; se reintenta sola pasado un tiempo.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
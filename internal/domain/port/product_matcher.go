@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"meli-product-api/internal/domain/model"
+)
+
+// ProductMatcher evalúa criterios de búsqueda contra productos puntuales.
+type ProductMatcher interface {
+	// Matching devuelve cuáles de ids cumplen criteria; con ids nil
+	// considera todo el catálogo.
+	Matching(ctx context.Context, criteria model.SearchCriteria, ids []string) ([]string, error)
+}
@@ -7,7 +7,7 @@ import (
 
 type ProductRepository interface {
 	FindByID(ctx context.Context, id string) (*model.Product, error)
-	Search(ctx context.Context, keyword string, limit, offset int) ([]model.Product, error)
-	Count(ctx context.Context, keyword string) (int, error)
+	Search(ctx context.Context, criteria model.SearchCriteria, page model.Page) (*model.SearchPage, error)
+	Count(ctx context.Context, criteria model.SearchCriteria) (int, error)
 	FindRelated(ctx context.Context, productID, category string, limit int) ([]model.Product, error)
 }
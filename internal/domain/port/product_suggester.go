@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"meli-product-api/internal/domain/model"
+)
+
+// ProductSuggester resuelve el autocompletado de la caja de búsqueda
+type ProductSuggester interface {
+	Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error)
+}
@@ -2,9 +2,13 @@ package port
 
 import (
 	"context"
+	"errors"
 	"meli-product-api/internal/domain/model"
 )
 
+// ErrQuestionsNotFound: el servicio de preguntas no conoce el producto.
+var ErrQuestionsNotFound = errors.New("product questions not found")
+
 // QuestionClient simula llamada HTTP a microservicio de Questions
 type QuestionClient interface {
 	GetByProductID(ctx context.Context, productID string, limit int) ([]model.Question, error)
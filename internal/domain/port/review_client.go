@@ -2,9 +2,13 @@ package port
 
 import (
 	"context"
+	"errors"
 	"meli-product-api/internal/domain/model"
 )
 
+// ErrReviewsNotFound: el servicio de opiniones no conoce el producto.
+var ErrReviewsNotFound = errors.New("product reviews not found")
+
 // ReviewClient simula llamada HTTP a microservicio de Reviews
 type ReviewClient interface {
 	GetByProductID(ctx context.Context, productID string) ([]model.Review, error)
@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"meli-product-api/internal/domain/model"
+)
+
+var (
+	// ErrSavedSearchNotFound indica una búsqueda guardada inexistente o de
+	// otro usuario.
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+	// ErrSavedSearchLimit indica que el usuario ya llegó al máximo de
+	// búsquedas guardadas.
+	ErrSavedSearchLimit = errors.New("saved search limit reached")
+)
+
+type SavedSearchRepository interface {
+	// Create guarda la búsqueda si su usuario tiene menos de maxPerUser;
+	// si no, devuelve ErrSavedSearchLimit. Verificar y guardar es atómico.
+	Create(ctx context.Context, search model.SavedSearch, maxPerUser int) error
+	FindByUser(ctx context.Context, userID string) ([]model.SavedSearch, error)
+	FindAll(ctx context.Context) ([]model.SavedSearch, error)
+	Delete(ctx context.Context, userID, id string) error
+}
+
+type NotificationRepository interface {
+	// Notify registra las notificaciones cuyo producto todavía no era
+	// conocido por su búsqueda guardada y lo marca como conocido. Devuelve
+	// cuántas registró.
+	Notify(ctx context.Context, notifications []model.Notification) (int, error)
+	// FindNotifications devuelve las notificaciones del usuario, las más
+	// recientes primero.
+	FindNotifications(ctx context.Context, userID string, limit int) ([]model.Notification, error)
+}
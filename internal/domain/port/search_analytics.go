@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+	"meli-product-api/internal/domain/model"
+	"time"
+)
+
+// SearchAnalytics registra las búsquedas y las resume por ventanas de
+// tiempo hacia atrás desde ahora.
+type SearchAnalytics interface {
+	Record(ctx context.Context, event model.SearchEvent) error
+	// TopQueries agrupa por query, sin importar los filtros.
+	TopQueries(ctx context.Context, window time.Duration, limit int) ([]model.QueryStats, error)
+	// ZeroResultQueries agrupa por query y filtros.
+	ZeroResultQueries(ctx context.Context, window time.Duration, limit int) ([]model.QueryStats, error)
+	SlowestSearches(ctx context.Context, window time.Duration, limit int) ([]model.SearchEvent, error)
+}
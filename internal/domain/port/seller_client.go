@@ -2,9 +2,12 @@ package port
 
 import (
 	"context"
+	"errors"
 	"meli-product-api/internal/domain/model"
 )
 
+var ErrSellerNotFound = errors.New("seller not found")
+
 // SellerClient simula llamada HTTP a microservicio de Sellers
 type SellerClient interface {
 	GetByID(ctx context.Context, sellerID string) (*model.Seller, error)
@@ -0,0 +1,11 @@
+package port
+
+import "context"
+
+// SpellChecker propone correcciones ortográficas a partir de las palabras
+// del catálogo (títulos, marcas y categorías)
+type SpellChecker interface {
+	// Correct devuelve, para cada palabra, su reemplazo: la misma palabra si
+	// es conocida, una o más palabras si encontró una corrección.
+	Correct(ctx context.Context, words []string) ([][]string, error)
+}
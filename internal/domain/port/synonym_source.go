@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+	"meli-product-api/internal/domain/query"
+)
+
+// SynonymSource provee el diccionario de sinónimos vigente. Puede cambiar
+// entre llamadas si la fuente se recarga.
+type SynonymSource interface {
+	Synonyms(ctx context.Context) (*query.Synonyms, error)
+}
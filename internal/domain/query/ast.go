@@ -0,0 +1,192 @@
+package query
+
+import "meli-product-api/internal/domain/analysis"
+
+// Campos que aceptan calificador (brand:apple)
+const (
+	FieldTitle       = "title"
+	FieldBrand       = "brand"
+	FieldModel       = "model"
+	FieldCategory    = "category"
+	FieldDescription = "description"
+	FieldAttributes  = "attributes"
+)
+
+var fields = map[string]bool{
+	FieldTitle:       true,
+	FieldBrand:       true,
+	FieldModel:       true,
+	FieldCategory:    true,
+	FieldDescription: true,
+	FieldAttributes:  true,
+}
+
+// Node es un nodo del árbol de la query: *Term, *And, *Or o *Not.
+type Node interface {
+	isNode()
+}
+
+// Term es una palabra o una frase entre comillas, opcionalmente
+// restringida a un campo.
+type Term struct {
+	Field  string // "" busca en todos los campos
+	Text   string // texto tal como lo escribió el usuario, sin comillas
+	Phrase bool
+	// Synonym marca las alternativas agregadas por Synonyms.Expand; no
+	// tienen ubicación en la query original.
+	Synonym bool
+	// Start y End ubican Text dentro de la query original (bytes)
+	Start int
+	End   int
+
+	// Terms y Positions los completa Analyze. Positions es relativa al
+	// primer término y conserva los huecos de las stopwords, así
+	// "celulares y teléfonos" exige "telefon" dos lugares después de
+	// "celular".
+	Terms     []string
+	Positions []int
+}
+
+// And exige todos sus hijos; los *Not restan resultados.
+type And struct {
+	Children []Node
+}
+
+type Or struct {
+	Children []Node
+}
+
+// Not excluye los documentos de Child. Solo aparece dentro de un *And con
+// al menos un hijo positivo.
+type Not struct {
+	Child Node
+}
+
+func (*Term) isNode() {}
+func (*And) isNode()  {}
+func (*Or) isNode()   {}
+func (*Not) isNode()  {}
+
+// Compile parsea la query y la analiza. Devuelve nil (sin error) si la
+// query es válida pero no tiene términos buscables, por ejemplo solo
+// stopwords.
+func Compile(input string, analyzer *analysis.Analyzer) (Node, error) {
+	node, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(node, analyzer)
+}
+
+// Analyze pasa cada término por el analizador y simplifica el árbol:
+// descarta hojas sin términos (stopwords) y grupos vacíos. Si un And se
+// queda solo con exclusiones ("la -iphone") devuelve un *SyntaxError, como
+// el parser con "-iphone": la exclusión no se descarta en silencio.
+func Analyze(node Node, analyzer *analysis.Analyzer) (Node, error) {
+	switch n := node.(type) {
+	case *Term:
+		tokens := analyzer.Analyze(n.Text)
+		if len(tokens) == 0 {
+			return nil, nil
+		}
+
+		t := *n
+		t.Terms = make([]string, len(tokens))
+		t.Positions = make([]int, len(tokens))
+		for i, tok := range tokens {
+			t.Terms[i] = tok.Term
+			t.Positions[i] = tok.Position - tokens[0].Position
+		}
+		return &t, nil
+
+	case *Not:
+		// Excluding only stopwords ("-la") excludes nothing
+		child, err := Analyze(n.Child, analyzer)
+		if child == nil || err != nil {
+			return nil, err
+		}
+		return &Not{Child: child}, nil
+
+	case *And:
+		var children []Node
+		positive := false
+		for _, c := range n.Children {
+			analyzed, err := Analyze(c, analyzer)
+			if err != nil {
+				return nil, err
+			}
+			if analyzed != nil {
+				children = append(children, analyzed)
+				if _, neg := analyzed.(*Not); !neg {
+					positive = true
+				}
+			}
+		}
+		if !positive {
+			if len(children) > 0 {
+				return nil, &SyntaxError{Msg: "an exclusion needs at least one positive term to exclude from, and common words like \"la\" or \"de\" are not searched"}
+			}
+			return nil, nil
+		}
+		if len(children) == 1 {
+			return children[0], nil
+		}
+		return &And{Children: children}, nil
+
+	case *Or:
+		var children []Node
+		for _, c := range n.Children {
+			analyzed, err := Analyze(c, analyzer)
+			if err != nil {
+				return nil, err
+			}
+			if analyzed != nil {
+				children = append(children, analyzed)
+			}
+		}
+		switch len(children) {
+		case 0:
+			return nil, nil
+		case 1:
+			return children[0], nil
+		}
+		return &Or{Children: children}, nil
+	}
+
+	return nil, nil
+}
+
+// Leaves devuelve los términos positivos (no excluidos) del árbol, en el
+// orden en que aparecen en la query.
+func Leaves(node Node) []*Term {
+	var leaves []*Term
+
+	var walk func(Node)
+	walk = func(n Node) {
+		switch n := n.(type) {
+		case *Term:
+			leaves = append(leaves, n)
+		case *And:
+			for _, c := range n.Children {
+				walk(c)
+			}
+		case *Or:
+			for _, c := range n.Children {
+				walk(c)
+			}
+		}
+	}
+	walk(node)
+
+	return leaves
+}
+
+// PositiveTerms devuelve los términos analizados de Leaves; son los que
+// cuentan para la relevancia.
+func PositiveTerms(node Node) []string {
+	var terms []string
+	for _, leaf := range Leaves(node) {
+		terms = append(terms, leaf.Terms...)
+	}
+	return terms
+}
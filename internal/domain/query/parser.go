@@ -0,0 +1,430 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError describe una query mal formada. Pos es la posición (en
+// caracteres, desde 1) donde se detectó el problema, o 0 si el problema
+// es de la query completa.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Pos == 0 {
+		return "invalid query: " + e.Msg
+	}
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokPhrase
+	tokOr
+	tokAnd
+	tokMinus
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  tokenKind
+	field string
+	text  string
+	start int // offset del token (incluye calificador y comillas)
+	// Offsets de text dentro de la query
+	textStart int
+	textEnd   int
+}
+
+// Parse convierte la query en un árbol. La gramática es:
+//
+//	query   = or
+//	or      = and { "OR" and }
+//	and     = unary { ["AND"] unary }
+//	unary   = ["-"] primary
+//	primary = "(" or ")" | [field ":"] ( word | '"' phrase '"' )
+//
+// Los términos consecutivos se combinan con AND implícito. OR y AND solo
+// son operadores en mayúsculas.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: input, tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek(), "query is empty")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %s", describe(tok))
+	}
+
+	if err := p.checkExclusions(node); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []Node{first}
+	for p.peek().kind == tokOr {
+		op := p.next()
+		if !startsTerm(p.peek().kind) {
+			return nil, p.errorf(op, "expected a term after OR")
+		}
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &Or{Children: children}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	var children []Node
+
+	for {
+		tok := p.peek()
+		if tok.kind == tokAnd {
+			p.next()
+			if len(children) == 0 || !startsTerm(p.peek().kind) {
+				return nil, p.errorf(tok, "AND must appear between two terms")
+			}
+			continue
+		}
+		if !startsTerm(tok.kind) {
+			break
+		}
+
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 0 {
+		tok := p.peek()
+		if tok.kind == tokOr {
+			return nil, p.errorf(tok, "expected a term before OR")
+		}
+		return nil, p.errorf(tok, "expected a term, found %s", describe(tok))
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &And{Children: children}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind != tokMinus {
+		return p.parsePrimary()
+	}
+
+	minus := p.next()
+	if kind := p.peek().kind; kind != tokWord && kind != tokPhrase && kind != tokLParen {
+		return nil, p.errorf(minus, "expected a term after '-'")
+	}
+
+	child, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return &Not{Child: child}, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokLParen:
+		if p.peek().kind == tokRParen {
+			return nil, p.errorf(tok, "empty parentheses")
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, p.errorf(tok, "missing closing parenthesis")
+		}
+		p.next()
+		return node, nil
+
+	case tokWord, tokPhrase:
+		return &Term{
+			Field:  tok.field,
+			Text:   tok.text,
+			Phrase: tok.kind == tokPhrase,
+			Start:  tok.textStart,
+			End:    tok.textEnd,
+		}, nil
+	}
+
+	return nil, p.errorf(tok, "expected a term, found %s", describe(tok))
+}
+
+// checkExclusions rechaza exclusiones que no restan de nada, como "-usado"
+// solo o "iphone OR -usado".
+func (p *parser) checkExclusions(node Node) error {
+	switch n := node.(type) {
+	case *Not:
+		return &SyntaxError{Msg: "an exclusion needs at least one positive term to exclude from"}
+	case *Or:
+		for _, c := range n.Children {
+			if _, neg := c.(*Not); neg {
+				return &SyntaxError{Msg: "exclusions cannot be combined with OR"}
+			}
+			if err := p.checkExclusions(c); err != nil {
+				return err
+			}
+		}
+	case *And:
+		positive := false
+		for _, c := range n.Children {
+			if not, neg := c.(*Not); neg {
+				if err := p.checkExclusionChild(not.Child); err != nil {
+					return err
+				}
+				continue
+			}
+			positive = true
+			if err := p.checkExclusions(c); err != nil {
+				return err
+			}
+		}
+		if !positive {
+			return &SyntaxError{Msg: "an exclusion needs at least one positive term to exclude from"}
+		}
+	}
+	return nil
+}
+
+// checkExclusionChild valida lo que está dentro de un "-( ... )".
+func (p *parser) checkExclusionChild(node Node) error {
+	switch n := node.(type) {
+	case *Not:
+		return &SyntaxError{Msg: "double exclusion is not allowed"}
+	case *And, *Or:
+		return p.checkExclusions(n)
+	}
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &SyntaxError{
+		Pos: utf8.RuneCountInString(p.input[:tok.start]) + 1,
+		Msg: fmt.Sprintf(format, args...),
+	}
+}
+
+func startsTerm(kind tokenKind) bool {
+	return kind == tokWord || kind == tokPhrase || kind == tokMinus || kind == tokLParen
+}
+
+func describe(tok token) string {
+	switch tok.kind {
+	case tokEOF:
+		return "end of query"
+	case tokOr:
+		return "OR"
+	case tokAnd:
+		return "AND"
+	case tokMinus:
+		return "'-'"
+	case tokLParen:
+		return "'('"
+	case tokRParen:
+		return "')'"
+	}
+	return fmt.Sprintf("%q", tok.text)
+}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, start: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, start: i})
+			i++
+
+		case r == '-' && atTermStart(input, i):
+			tokens = append(tokens, token{kind: tokMinus, start: i})
+			i++
+
+		case r == '"':
+			tok, end, err := lexPhrase(input, i, i, "")
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = end
+
+		default:
+			tok, end, err := lexWord(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, start: len(input)}), nil
+}
+
+// atTermStart indica si el '-' en i es una exclusión: debe venir pegado a
+// un término ("-usado"), no suelto ni dentro de una palabra ("wi-fi").
+func atTermStart(input string, i int) bool {
+	if i+1 >= len(input) {
+		return true // a trailing '-' is reported by the parser
+	}
+	next, _ := utf8.DecodeRuneInString(input[i+1:])
+	return !unicode.IsSpace(next)
+}
+
+func lexPhrase(input string, start, quote int, field string) (token, int, error) {
+	closing := strings.IndexByte(input[quote+1:], '"')
+	if closing < 0 {
+		return token{}, 0, &SyntaxError{
+			Pos: utf8.RuneCountInString(input[:quote]) + 1,
+			Msg: "unterminated quoted phrase",
+		}
+	}
+
+	textStart := quote + 1
+	textEnd := textStart + closing
+	if strings.TrimSpace(input[textStart:textEnd]) == "" {
+		return token{}, 0, &SyntaxError{
+			Pos: utf8.RuneCountInString(input[:quote]) + 1,
+			Msg: "empty quoted phrase",
+		}
+	}
+
+	return token{
+		kind:      tokPhrase,
+		field:     field,
+		text:      input[textStart:textEnd],
+		start:     start,
+		textStart: textStart,
+		textEnd:   textEnd,
+	}, textEnd + 1, nil
+}
+
+func lexWord(input string, start int) (token, int, error) {
+	end := start
+	for end < len(input) {
+		r, size := utf8.DecodeRuneInString(input[end:])
+		if unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' {
+			break
+		}
+		end += size
+	}
+	word := input[start:end]
+
+	if word == "OR" {
+		return token{kind: tokOr, text: word, start: start}, end, nil
+	}
+	if word == "AND" {
+		return token{kind: tokAnd, text: word, start: start}, end, nil
+	}
+
+	colon := strings.IndexByte(word, ':')
+	if colon <= 0 || !isIdentifier(word[:colon]) {
+		return token{kind: tokWord, text: word, start: start, textStart: start, textEnd: end}, end, nil
+	}
+
+	field := strings.ToLower(word[:colon])
+	if !fields[field] {
+		return token{}, 0, &SyntaxError{
+			Pos: utf8.RuneCountInString(input[:start]) + 1,
+			Msg: fmt.Sprintf("unknown field %q (valid fields: attributes, brand, category, description, model, title)", word[:colon]),
+		}
+	}
+
+	valueStart := start + colon + 1
+	if valueStart == end {
+		// brand:"..." or a dangling qualifier
+		if end < len(input) && input[end] == '"' {
+			return lexPhrase(input, start, end, field)
+		}
+		return token{}, 0, &SyntaxError{
+			Pos: utf8.RuneCountInString(input[:start]) + 1,
+			Msg: fmt.Sprintf("missing value for field %q", field),
+		}
+	}
+
+	return token{
+		kind:      tokWord,
+		field:     field,
+		text:      input[valueStart:end],
+		start:     start,
+		textStart: valueStart,
+		textEnd:   end,
+	}, end, nil
+}
+
+func isIdentifier(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
+		}
+	}
+	return s != ""
+}
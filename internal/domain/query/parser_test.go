@@ -0,0 +1,128 @@
+package query
+
+import (
+	"errors"
+	"meli-product-api/internal/domain/analysis"
+	"strings"
+	"testing"
+)
+
+// render escribe el árbol en una forma compacta para comparar: los And
+// entre corchetes, los Or entre llaves.
+func render(node Node) string {
+	switch n := node.(type) {
+	case *Term:
+		text := n.Text
+		if len(n.Terms) > 0 {
+			text = strings.Join(n.Terms, " ")
+		}
+		if n.Phrase {
+			text = `"` + text + `"`
+		}
+		if n.Field != "" {
+			text = n.Field + ":" + text
+		}
+		return text
+	case *Not:
+		return "-" + render(n.Child)
+	case *And:
+		return "[" + renderAll(n.Children) + "]"
+	case *Or:
+		return "{" + renderAll(n.Children) + "}"
+	}
+	return "<nil>"
+}
+
+func renderAll(nodes []Node) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = render(n)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{input: "iphone", want: "iphone"},
+		{input: "iphone 15 pro", want: "[iphone 15 pro]"},
+		{input: "iphone AND funda", want: "[iphone funda]"},
+		{input: "samsung OR motorola", want: "{samsung motorola}"},
+		{input: "celular (samsung OR motorola) -usado", want: "[celular {samsung motorola} -usado]"},
+		{input: `"smart tv" brand:samsung`, want: `["smart tv" brand:samsung]`},
+		{input: "zapatillas -(usado OR reacondicionado)", want: "[zapatillas -{usado reacondicionado}]"},
+		{input: "wi-fi or", want: "[wi-fi or]"},
+		{input: "", wantErr: "query is empty"},
+		{input: "-usado", wantErr: "an exclusion needs at least one positive term"},
+		{input: "iphone OR -usado", wantErr: "exclusions cannot be combined with OR"},
+		{input: "iphone -(-usado)", wantErr: "double exclusion is not allowed"},
+		{input: "OR iphone", wantErr: "expected a term before OR"},
+		{input: "iphone OR", wantErr: "expected a term after OR"},
+		{input: "AND iphone", wantErr: "AND must appear between two terms"},
+		{input: "(iphone", wantErr: "missing closing parenthesis"},
+		{input: "iphone ()", wantErr: "empty parentheses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Parse(tt.input)
+
+			if tt.wantErr != "" {
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() err = %v, want a SyntaxError with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() err = %v", err)
+			}
+			if got := render(node); got != tt.want {
+				t.Errorf("Parse() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	analyzer := analysis.NewAnalyzer()
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "Teléfonos celulares", want: "[telefon celular]"},
+		{input: "funda para iphone", want: "[fund iphon]"},
+		{input: `"funda para iphone"`, want: `"fund iphon"`},
+		{input: "la de", want: "<nil>"},
+		{input: "(la OR de) iphone", want: "iphon"},
+		{input: "iphone -la", want: "iphon"},
+		{input: "samsung -usado", want: "[samsung -usad]"},
+		{input: "la -iphone", wantErr: true},
+		{input: "(la -iphone) OR samsung", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			node, err := Compile(tt.input, analyzer)
+
+			if tt.wantErr {
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("Compile() = %s, %v, want a SyntaxError", render(node), err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compile() err = %v", err)
+			}
+			if got := render(node); got != tt.want {
+				t.Errorf("Compile() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package query
+
+import (
+	"meli-product-api/internal/domain/analysis"
+	"strings"
+)
+
+// Synonyms expande los términos de una query con sus sinónimos. Las
+// claves se comparan ya analizadas, así "celulares" usa las reglas de
+// "celular".
+type Synonyms struct {
+	rules map[string][]*Term
+	// maxWords es el largo (en términos) de la clave más larga
+	maxWords int
+}
+
+// NewSynonyms arma el diccionario a partir de grupos de equivalencia (cada
+// miembro se expande a todos los demás) y de expansiones en un solo
+// sentido (buscar la clave también trae sus destinos, pero no al revés).
+func NewSynonyms(analyzer *analysis.Analyzer, equivalent [][]string, oneWay map[string][]string) *Synonyms {
+	s := &Synonyms{rules: make(map[string][]*Term)}
+
+	for _, group := range equivalent {
+		for _, from := range group {
+			for _, to := range group {
+				s.add(analyzer, from, to)
+			}
+		}
+	}
+	for from, targets := range oneWay {
+		for _, to := range targets {
+			s.add(analyzer, from, to)
+		}
+	}
+
+	return s
+}
+
+// Len devuelve la cantidad de claves con al menos una expansión.
+func (s *Synonyms) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rules)
+}
+
+func (s *Synonyms) add(analyzer *analysis.Analyzer, from, to string) {
+	fromTerms := analyzer.Terms(from)
+	// Analyzing a lone term never fails
+	alt, _ := Analyze(&Term{Text: to, Synonym: true}, analyzer)
+	if len(fromTerms) == 0 || alt == nil {
+		return
+	}
+
+	key := strings.Join(fromTerms, " ")
+	term := alt.(*Term)
+	if strings.Join(term.Terms, " ") == key {
+		return
+	}
+	term.Phrase = len(term.Terms) > 1
+
+	for _, existing := range s.rules[key] {
+		if strings.Join(existing.Terms, " ") == strings.Join(term.Terms, " ") {
+			return
+		}
+	}
+	s.rules[key] = append(s.rules[key], term)
+	s.maxWords = max(s.maxWords, len(fromTerms))
+}
+
+// Expand devuelve una copia del árbol (ya analizado) donde cada término
+// con sinónimos pasa a ser un *Or entre el original y sus alternativas.
+// Las alternativas no se vuelven a expandir.
+func (s *Synonyms) Expand(node Node) Node {
+	if s.Len() == 0 || node == nil {
+		return node
+	}
+
+	switch n := node.(type) {
+	case *Term:
+		return s.expandTerm(n)
+	case *Not:
+		return &Not{Child: s.Expand(n.Child)}
+	case *Or:
+		children := make([]Node, len(n.Children))
+		for i, c := range n.Children {
+			children[i] = s.Expand(c)
+		}
+		return &Or{Children: children}
+	case *And:
+		return s.expandAnd(n)
+	}
+	return node
+}
+
+func (s *Synonyms) expandTerm(t *Term) Node {
+	alts := s.rules[strings.Join(t.Terms, " ")]
+	if len(alts) == 0 {
+		return t
+	}
+
+	children := []Node{t}
+	for _, alt := range alts {
+		a := *alt
+		a.Field = t.Field
+		children = append(children, &a)
+	}
+	return &Or{Children: children}
+}
+
+// expandAnd además reconoce sinónimos de varias palabras escritas sueltas
+// ("teléfono móvil" sin comillas), que el parser dejó como hojas vecinas.
+func (s *Synonyms) expandAnd(n *And) Node {
+	var children []Node
+
+	for i := 0; i < len(n.Children); {
+		if size, alts := s.longestRun(n.Children[i:]); size > 1 {
+			run := &And{Children: n.Children[i : i+size]}
+			group := []Node{run}
+			for _, alt := range alts {
+				a := *alt
+				group = append(group, &a)
+			}
+			children = append(children, &Or{Children: group})
+			i += size
+			continue
+		}
+
+		children = append(children, s.Expand(n.Children[i]))
+		i++
+	}
+
+	return &And{Children: children}
+}
+
+// longestRun busca la clave de varias palabras más larga formada por las
+// primeras hojas simples (sin campo ni comillas) de children.
+func (s *Synonyms) longestRun(children []Node) (int, []*Term) {
+	var words []string
+	for _, c := range children {
+		t, ok := c.(*Term)
+		if !ok || t.Field != "" || t.Phrase || len(t.Terms) != 1 || len(words) == s.maxWords {
+			break
+		}
+		words = append(words, t.Terms[0])
+	}
+
+	for size := len(words); size > 1; size-- {
+		if alts := s.rules[strings.Join(words[:size], " ")]; len(alts) > 0 {
+			return size, alts
+		}
+	}
+	return 0, nil
+}
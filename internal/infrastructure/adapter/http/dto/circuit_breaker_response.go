@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"meli-product-api/internal/domain/model"
+	"time"
+)
+
+type CircuitBreakersResponse struct {
+	CircuitBreakers []CircuitBreakerDTO `json:"circuit_breakers"`
+}
+
+// CircuitBreakerDTO: requests, failures y failure_rate cuentan la ventana
+// vigente; since es desde cuándo está en el estado actual.
+type CircuitBreakerDTO struct {
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	Since       time.Time `json:"since"`
+	Requests    int       `json:"requests"`
+	Failures    int       `json:"failures"`
+	FailureRate float64   `json:"failure_rate"`
+}
+
+func ToCircuitBreakersResponse(statuses []model.CircuitStatus) *CircuitBreakersResponse {
+	breakers := make([]CircuitBreakerDTO, len(statuses))
+	for i, s := range statuses {
+		breakers[i] = CircuitBreakerDTO{
+			Name:        s.Name,
+			State:       string(s.State),
+			Since:       s.Since,
+			Requests:    s.Requests,
+			Failures:    s.Failures,
+			FailureRate: s.FailureRate,
+		}
+	}
+
+	return &CircuitBreakersResponse{CircuitBreakers: breakers}
+}
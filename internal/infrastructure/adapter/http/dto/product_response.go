@@ -1,17 +1,31 @@
 package dto
 
 import (
+	"bytes"
+	"encoding/json"
 	"meli-product-api/internal/domain/model"
+	"reflect"
+	"strings"
 	"time"
 )
 
+// ProductDetailsResponse omite las secciones que no se pidieron con
+// include; una pedida que vino vacía sí aparece.
 type ProductDetailsResponse struct {
 	Product         ProductDTO          `json:"product"`
-	Seller          SellerDTO           `json:"seller"`
+	Seller          *SellerDTO          `json:"seller,omitempty"`
 	Shipping        ShippingDTO         `json:"shipping"`
-	Reviews         ReviewsDTO          `json:"reviews"`
-	Questions       []QuestionDTO       `json:"questions"`
-	RelatedProducts []RelatedProductDTO `json:"related_products"`
+	Reviews         *ReviewsDTO         `json:"reviews,omitempty"`
+	Questions       []QuestionDTO       `json:"questions,omitzero"`
+	RelatedProducts []RelatedProductDTO `json:"related_products,omitzero"`
+	// DegradedSections aparece si alguna sección falló o venció y se
+	// devolvió vacía (o, el vendedor, con datos por defecto)
+	DegradedSections []DegradedSectionDTO `json:"degraded_sections,omitempty"`
+}
+
+type DegradedSectionDTO struct {
+	Section string `json:"section"`
+	Reason  string `json:"reason"`
 }
 
 type ProductDTO struct {
@@ -29,6 +43,57 @@ type ProductDTO struct {
 	Attributes        []AttributeDTO `json:"attributes"`
 	Brand             string         `json:"brand"`
 	Model             string         `json:"model"`
+
+	// fields limita los campos serializados; nil los incluye todos
+	fields map[string]bool
+}
+
+// ProductFields son los campos de ProductDTO que se pueden pedir con
+// fields=, en el orden en que se serializan. id se incluye siempre.
+var ProductFields = jsonFieldNames(reflect.TypeFor[ProductDTO]())
+
+// MarshalJSON serializa solo los campos pedidos, si se pidieron.
+func (p ProductDTO) MarshalJSON() ([]byte, error) {
+	type plain ProductDTO
+	data, err := json.Marshal(plain(p))
+	if err != nil || p.fields == nil {
+		return data, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for _, name := range ProductFields {
+		value, ok := all[name]
+		if !ok || (name != "id" && !p.fields[name]) {
+			continue
+		}
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.IsExported() && name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 type AttributeDTO struct {
@@ -90,15 +155,48 @@ type RelatedProductDTO struct {
 }
 
 // Mapper functions
-func ToProductDetailsResponse(details *model.ProductDetails) *ProductDetailsResponse {
-	return &ProductDetailsResponse{
-		Product:         toProductDTO(details.Product),
-		Seller:          toSellerDTO(details.Seller),
-		Shipping:        toShippingDTO(details.Shipping),
-		Reviews:         toReviewsDTO(details.Reviews, details.AverageRating, details.TotalReviews),
-		Questions:       toQuestionDTOs(details.Questions),
-		RelatedProducts: toRelatedProductDTOs(details.RelatedProducts),
+
+// ToProductDetailsResponse incluye solo las secciones de sections y, si
+// fields no es nil, solo esos campos del producto.
+func ToProductDetailsResponse(details *model.ProductDetails, sections model.DetailSections, fields map[string]bool) *ProductDetailsResponse {
+	response := &ProductDetailsResponse{
+		Product:          toProductDTO(details.Product),
+		Shipping:         toShippingDTO(details.Shipping),
+		DegradedSections: toDegradedSectionDTOs(details.Degraded),
+	}
+	response.Product.fields = fields
+
+	if sections.Has(model.SectionSeller) {
+		seller := toSellerDTO(details.Seller)
+		response.Seller = &seller
 	}
+	if sections.Has(model.SectionReviews) {
+		reviews := toReviewsDTO(details.Reviews, details.AverageRating, details.TotalReviews)
+		response.Reviews = &reviews
+	}
+	if sections.Has(model.SectionQuestions) {
+		response.Questions = toQuestionDTOs(details.Questions)
+	}
+	if sections.Has(model.SectionRelated) {
+		response.RelatedProducts = toRelatedProductDTOs(details.RelatedProducts)
+	}
+
+	return response
+}
+
+func toDegradedSectionDTOs(sections []model.DegradedSection) []DegradedSectionDTO {
+	if len(sections) == 0 {
+		return nil
+	}
+
+	dtos := make([]DegradedSectionDTO, len(sections))
+	for i, s := range sections {
+		dtos[i] = DegradedSectionDTO{
+			Section: s.Section,
+			Reason:  s.Reason,
+		}
+	}
+	return dtos
 }
 
 func toProductDTO(p model.Product) ProductDTO {
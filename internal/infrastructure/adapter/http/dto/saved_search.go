@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"fmt"
+	"meli-product-api/internal/domain/model"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CreateSavedSearchRequest usa los mismos nombres de filtro que
+// /products/search: {"q": "iphone", "filters": {"price_max": 500000,
+// "attr[Color]": ["Negro", "Blanco"]}}.
+type CreateSavedSearchRequest struct {
+	Name    string         `json:"name"`
+	Query   string         `json:"q"`
+	Filters map[string]any `json:"filters"`
+}
+
+// Params convierte los filtros a parámetros de búsqueda. Acepta textos,
+// números, booleanos y listas de textos.
+func (r CreateSavedSearchRequest) Params() (url.Values, error) {
+	params := url.Values{}
+	for name, value := range r.Filters {
+		switch v := value.(type) {
+		case string:
+			params.Add(name, v)
+		case float64:
+			params.Add(name, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			params.Add(name, strconv.FormatBool(v))
+		case []any:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid filter '%s': list values must be strings", name)
+				}
+				params.Add(name, s)
+			}
+		default:
+			return nil, fmt.Errorf("invalid filter '%s': must be a string, number, boolean or list of strings", name)
+		}
+	}
+	return params, nil
+}
+
+type SavedSearchDTO struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Query     string         `json:"q"`
+	Filters   map[string]any `json:"filters"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type SavedSearchListResponse struct {
+	SavedSearches []SavedSearchDTO `json:"saved_searches"`
+}
+
+type NotificationDTO struct {
+	ID              string    `json:"id"`
+	SavedSearchID   string    `json:"saved_search_id"`
+	SavedSearchName string    `json:"saved_search_name"`
+	ProductID       string    `json:"product_id"`
+	Title           string    `json:"title"`
+	Price           float64   `json:"price"`
+	Reason          string    `json:"reason"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationDTO `json:"notifications"`
+}
+
+func ToSavedSearchDTO(s model.SavedSearch) SavedSearchDTO {
+	// Same parameter names the search endpoint and the request use
+	params, _ := url.ParseQuery(s.Filters.String())
+	filters := make(map[string]any, len(params))
+	for name, values := range params {
+		if len(values) == 1 {
+			filters[name] = values[0]
+		} else {
+			filters[name] = values
+		}
+	}
+
+	return SavedSearchDTO{
+		ID:        s.ID,
+		Name:      s.Name,
+		Query:     s.Keyword,
+		Filters:   filters,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func ToSavedSearchListResponse(searches []model.SavedSearch) *SavedSearchListResponse {
+	dtos := make([]SavedSearchDTO, len(searches))
+	for i, s := range searches {
+		dtos[i] = ToSavedSearchDTO(s)
+	}
+	return &SavedSearchListResponse{SavedSearches: dtos}
+}
+
+func ToNotificationListResponse(notifications []model.Notification) *NotificationListResponse {
+	dtos := make([]NotificationDTO, len(notifications))
+	for i, n := range notifications {
+		dtos[i] = NotificationDTO{
+			ID:              n.ID,
+			SavedSearchID:   n.SavedSearchID,
+			SavedSearchName: n.SavedSearchName,
+			ProductID:       n.ProductID,
+			Title:           n.Title,
+			Price:           n.Price,
+			Reason:          n.Reason,
+			CreatedAt:       n.CreatedAt,
+		}
+	}
+	return &NotificationListResponse{Notifications: dtos}
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"meli-product-api/internal/domain/model"
+	"time"
+)
+
+type QueryStatsResponse struct {
+	Window  string          `json:"window"`
+	Since   time.Time       `json:"since"`
+	Queries []QueryStatsDTO `json:"queries"`
+}
+
+type QueryStatsDTO struct {
+	Query          string    `json:"query"`
+	Filters        string    `json:"filters,omitempty"`
+	Searches       int       `json:"searches"`
+	ZeroResults    int       `json:"zero_results"`
+	AvgLatencyMs   float64   `json:"avg_latency_ms"`
+	LastSearchedAt time.Time `json:"last_searched_at"`
+}
+
+type SlowSearchesResponse struct {
+	Window   string          `json:"window"`
+	Since    time.Time       `json:"since"`
+	Searches []SlowSearchDTO `json:"searches"`
+}
+
+type SlowSearchDTO struct {
+	Query      string    `json:"query"`
+	Filters    string    `json:"filters,omitempty"`
+	Results    int       `json:"results"`
+	LatencyMs  float64   `json:"latency_ms"`
+	SearchedAt time.Time `json:"searched_at"`
+}
+
+func ToQueryStatsResponse(window time.Duration, since time.Time, stats []model.QueryStats) *QueryStatsResponse {
+	queries := make([]QueryStatsDTO, len(stats))
+	for i, s := range stats {
+		queries[i] = QueryStatsDTO{
+			Query:          s.Query,
+			Filters:        s.Filters,
+			Searches:       s.Searches,
+			ZeroResults:    s.ZeroResults,
+			AvgLatencyMs:   milliseconds(s.AvgLatency),
+			LastSearchedAt: s.LastSearchedAt,
+		}
+	}
+
+	return &QueryStatsResponse{
+		Window:  window.String(),
+		Since:   since,
+		Queries: queries,
+	}
+}
+
+func ToSlowSearchesResponse(window time.Duration, since time.Time, events []model.SearchEvent) *SlowSearchesResponse {
+	searches := make([]SlowSearchDTO, len(events))
+	for i, e := range events {
+		searches[i] = SlowSearchDTO{
+			Query:      e.Query,
+			Filters:    e.Filters,
+			Results:    e.Results,
+			LatencyMs:  milliseconds(e.Latency),
+			SearchedAt: e.At,
+		}
+	}
+
+	return &SlowSearchesResponse{
+		Window:   window.String(),
+		Since:    since,
+		Searches: searches,
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -1,62 +1,314 @@
 package dto
 
-import "meli-product-api/internal/domain/model"
+import (
+	"html"
+	"meli-product-api/internal/domain/model"
+	"strings"
+)
 
 type ProductSearchResponse struct {
-	Query        string              `json:"query"`
-	TotalResults int                 `json:"total_results"`
-	Limit        int                 `json:"limit"`
-	Offset       int                 `json:"offset"`
-	Results      []ProductSummaryDTO `json:"results"`
+	Query         string `json:"query"`
+	Suggestion    string `json:"suggestion,omitempty"`
+	AutoCorrected bool   `json:"auto_corrected,omitempty"`
+	TotalResults  int    `json:"total_results"`
+	// TotalGroups solo aparece con group_by; total_results sigue contando
+	// publicaciones
+	TotalGroups *int   `json:"total_groups,omitempty"`
+	Limit       int    `json:"limit"`
+	Offset      int    `json:"offset"`
+	NextCursor  string `json:"next_cursor,omitempty"`
+	// Interpretation aparece si se dedujeron filtros del texto de q
+	Interpretation *InterpretationDTO  `json:"interpretation,omitempty"`
+	Results        []ProductSummaryDTO `json:"results"`
+	Facets         FacetsDTO           `json:"facets"`
+	// PriceHistogram se omite si no hubo resultados
+	PriceHistogram *PriceHistogramDTO `json:"price_histogram,omitempty"`
 }
 
 type ProductSummaryDTO struct {
-	ID                string   `json:"id"`
-	Title             string   `json:"title"`
-	Price             float64  `json:"price"`
-	OriginalPrice     *float64 `json:"original_price,omitempty"`
-	DiscountPercent   *int     `json:"discount_percentage,omitempty"`
-	Condition         string   `json:"condition"`
-	Thumbnail         string   `json:"thumbnail,omitempty"`
-	SoldQuantity      int      `json:"sold_quantity"`
-	AvailableQuantity int      `json:"available_quantity"`
-	Category          string   `json:"category"`
-	Brand             string   `json:"brand"`
-	FreeShipping      bool     `json:"free_shipping"`
-}
-
-func ToProductSearchResponse(query string, products []model.Product, total, limit, offset int) *ProductSearchResponse {
-	summaries := make([]ProductSummaryDTO, len(products))
-
-	for i, p := range products {
-		thumbnail := ""
-		if len(p.Images) > 0 {
-			thumbnail = p.Images[0]
-		}
-
-		freeShipping := p.Price > 50000
-
-		summaries[i] = ProductSummaryDTO{
-			ID:                p.ID,
-			Title:             p.Title,
-			Price:             p.Price,
-			OriginalPrice:     p.OriginalPrice,
-			DiscountPercent:   p.DiscountPercent,
-			Condition:         p.Condition,
-			Thumbnail:         thumbnail,
-			SoldQuantity:      p.SoldQuantity,
-			AvailableQuantity: p.AvailableQuantity,
-			Category:          p.Category,
-			Brand:             p.Brand,
-			FreeShipping:      freeShipping,
-		}
-	}
-
-	return &ProductSearchResponse{
-		Query:        query,
-		TotalResults: total,
-		Limit:        limit,
-		Offset:       offset,
-		Results:      summaries,
+	ID                string        `json:"id"`
+	Title             string        `json:"title"`
+	Price             float64       `json:"price"`
+	OriginalPrice     *float64      `json:"original_price,omitempty"`
+	DiscountPercent   *int          `json:"discount_percentage,omitempty"`
+	Condition         string        `json:"condition"`
+	Thumbnail         string        `json:"thumbnail,omitempty"`
+	SoldQuantity      int           `json:"sold_quantity"`
+	AvailableQuantity int           `json:"available_quantity"`
+	Category          string        `json:"category"`
+	Brand             string        `json:"brand"`
+	FreeShipping      bool          `json:"free_shipping"`
+	Score             *float64      `json:"score,omitempty"`
+	Highlight         *HighlightDTO `json:"highlight,omitempty"`
+	Variants          *VariantsDTO  `json:"variants,omitempty"`
+}
+
+// VariantsDTO resume las publicaciones agrupadas con el resultado
+// (incluido él) cuando se usa group_by.
+type VariantsDTO struct {
+	Count    int     `json:"count"`
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price"`
+}
+
+// InterpretationDTO lista los filtros deducidos de q, para mostrarlos como
+// chips; query es lo que quedó de q y efectivamente se buscó.
+type InterpretationDTO struct {
+	Query   string                 `json:"query"`
+	Filters []InterpretedFilterDTO `json:"filters"`
+}
+
+// InterpretedFilterDTO: param y value son el filtro equivalente
+// (price_range usa "min-max"); text es el fragmento de q que lo originó.
+type InterpretedFilterDTO struct {
+	Kind  string `json:"kind"`
+	Param string `json:"param"`
+	Value string `json:"value"`
+	Text  string `json:"text"`
+}
+
+// HighlightDTO repite el título y un fragmento de la descripción, escapados
+// como HTML, con las coincidencias envueltas en los marcadores
+// configurados.
+type HighlightDTO struct {
+	Title   string `json:"title"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// HighlightMarkers son los textos que rodean cada coincidencia resaltada.
+type HighlightMarkers struct {
+	Pre  string
+	Post string
+}
+
+type FacetsDTO struct {
+	Category     []FacetValueDTO `json:"category"`
+	Brand        []FacetValueDTO `json:"brand"`
+	Condition    []FacetValueDTO `json:"condition"`
+	FreeShipping []FacetValueDTO `json:"free_shipping"`
+	Price        []PriceRangeDTO `json:"price"`
+	// Attributes solo aparece cuando los resultados son de una única categoría
+	Attributes []AttributeFacetDTO `json:"attributes,omitempty"`
+}
+
+type AttributeFacetDTO struct {
+	Name   string          `json:"name"`
+	Values []FacetValueDTO `json:"values"`
+}
+
+type PriceHistogramDTO struct {
+	Min     float64              `json:"min"`
+	Max     float64              `json:"max"`
+	Buckets []HistogramBucketDTO `json:"buckets"`
+}
+
+type HistogramBucketDTO struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+type FacetValueDTO struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type PriceRangeDTO struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
+}
+
+// ToProductSearchResponse incluye el puntaje de relevancia solo si
+// withScores es true (modo debug). Los resultados que traen Highlight se
+// marcan con markers.
+func ToProductSearchResponse(query string, result *model.SearchResult, limit, offset int, withScores bool, markers HighlightMarkers) *ProductSearchResponse {
+	summaries := make([]ProductSummaryDTO, len(result.Hits))
+
+	for i, hit := range result.Hits {
+		summaries[i] = toProductSummaryDTO(hit.Product)
+
+		if withScores {
+			score := hit.Score
+			summaries[i].Score = &score
+		}
+
+		if hit.Group != nil {
+			summaries[i].Variants = &VariantsDTO{
+				Count:    hit.Group.Variants,
+				MinPrice: hit.Group.MinPrice,
+				MaxPrice: hit.Group.MaxPrice,
+			}
+		}
+
+		if hit.Highlight != nil {
+			summaries[i].Highlight = &HighlightDTO{
+				Title:   markers.wrap(hit.Highlight.Title),
+				Snippet: markers.wrap(hit.Highlight.Snippet),
+			}
+		}
+	}
+
+	response := &ProductSearchResponse{
+		Query:          query,
+		Suggestion:     result.Suggestion,
+		AutoCorrected:  result.AutoCorrected,
+		TotalResults:   result.Total,
+		Limit:          limit,
+		Offset:         offset,
+		Results:        summaries,
+		Interpretation: toInterpretationDTO(result.Interpretation),
+		Facets:         toFacetsDTO(result.Facets),
+		PriceHistogram: toPriceHistogramDTO(result.PriceHistogram),
+	}
+	if result.Groups > 0 {
+		groups := result.Groups
+		response.TotalGroups = &groups
+	}
+
+	return response
+}
+
+func toProductSummaryDTO(p model.Product) ProductSummaryDTO {
+	thumbnail := ""
+	if len(p.Images) > 0 {
+		thumbnail = p.Images[0]
+	}
+
+	return ProductSummaryDTO{
+		ID:                p.ID,
+		Title:             p.Title,
+		Price:             p.Price,
+		OriginalPrice:     p.OriginalPrice,
+		DiscountPercent:   p.DiscountPercent,
+		Condition:         p.Condition,
+		Thumbnail:         thumbnail,
+		SoldQuantity:      p.SoldQuantity,
+		AvailableQuantity: p.AvailableQuantity,
+		Category:          p.Category,
+		Brand:             p.Brand,
+		FreeShipping:      p.HasFreeShipping(),
+	}
+}
+
+func toInterpretationDTO(i *model.Interpretation) *InterpretationDTO {
+	if i == nil {
+		return nil
+	}
+
+	filters := make([]InterpretedFilterDTO, len(i.Filters))
+	for j, f := range i.Filters {
+		filters[j] = InterpretedFilterDTO{
+			Kind:  f.Kind,
+			Param: f.Param,
+			Value: f.Value,
+			Text:  f.Text,
+		}
+	}
+
+	return &InterpretationDTO{
+		Query:   i.Keyword,
+		Filters: filters,
+	}
+}
+
+// wrap escapa el texto como HTML y envuelve las coincidencias en los
+// marcadores, que son lo único que llega sin escapar.
+func (m HighlightMarkers) wrap(h model.HighlightedText) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range h.Matches {
+		b.WriteString(html.EscapeString(h.Text[last:span.Start]))
+		b.WriteString(m.Pre)
+		b.WriteString(html.EscapeString(h.Text[span.Start:span.End]))
+		b.WriteString(m.Post)
+		last = span.End
+	}
+	b.WriteString(html.EscapeString(h.Text[last:]))
+	return b.String()
+}
+
+func toFacetsDTO(f model.SearchFacets) FacetsDTO {
+	prices := make([]PriceRangeDTO, len(f.PriceRanges))
+	for i, pr := range f.PriceRanges {
+		prices[i] = PriceRangeDTO{
+			Min:   pr.Min,
+			Max:   pr.Max,
+			Count: pr.Count,
+		}
+	}
+
+	var attributes []AttributeFacetDTO
+	for _, a := range f.Attributes {
+		attributes = append(attributes, AttributeFacetDTO{
+			Name:   a.Name,
+			Values: toFacetValueDTOs(a.Values),
+		})
+	}
+
+	return FacetsDTO{
+		Category:     toFacetValueDTOs(f.Categories),
+		Brand:        toFacetValueDTOs(f.Brands),
+		Condition:    toFacetValueDTOs(f.Conditions),
+		FreeShipping: toFacetValueDTOs(f.FreeShipping),
+		Price:        prices,
+		Attributes:   attributes,
+	}
+}
+
+func toPriceHistogramDTO(h *model.PriceHistogram) *PriceHistogramDTO {
+	if h == nil {
+		return nil
+	}
+
+	buckets := make([]HistogramBucketDTO, len(h.Buckets))
+	for i, b := range h.Buckets {
+		buckets[i] = HistogramBucketDTO{
+			Min:   b.Min,
+			Max:   b.Max,
+			Count: b.Count,
+		}
+	}
+
+	return &PriceHistogramDTO{
+		Min:     h.Min,
+		Max:     h.Max,
+		Buckets: buckets,
+	}
+}
+
+func toFacetValueDTOs(values []model.FacetValue) []FacetValueDTO {
+	dtos := make([]FacetValueDTO, len(values))
+	for i, v := range values {
+		dtos[i] = FacetValueDTO{
+			Value: v.Value,
+			Count: v.Count,
+		}
+	}
+	return dtos
+}
+
+type SuggestResponse struct {
+	Query       string          `json:"query"`
+	Suggestions []SuggestionDTO `json:"suggestions"`
+}
+
+type SuggestionDTO struct {
+	Text string `json:"text"`
+	Kind string `json:"kind"`
+}
+
+func ToSuggestResponse(query string, suggestions []model.Suggestion) *SuggestResponse {
+	dtos := make([]SuggestionDTO, len(suggestions))
+	for i, s := range suggestions {
+		dtos[i] = SuggestionDTO{
+			Text: s.Text,
+			Kind: s.Kind,
+		}
+	}
+
+	return &SuggestResponse{
+		Query:       query,
+		Suggestions: dtos,
 	}
 }
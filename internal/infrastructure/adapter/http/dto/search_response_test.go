@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/model"
+	"testing"
+)
+
+func TestHighlightMarkersWrap(t *testing.T) {
+	markers := HighlightMarkers{Pre: "<em>", Post: "</em>"}
+
+	tests := []struct {
+		name string
+		text model.HighlightedText
+		want string
+	}{
+		{
+			name: "no matches",
+			text: model.HighlightedText{Text: "Smart TV"},
+			want: "Smart TV",
+		},
+		{
+			name: "match in the middle",
+			text: model.HighlightedText{
+				Text:    "Smart TV Samsung",
+				Matches: []analysis.Span{{Start: 9, End: 16}},
+			},
+			want: "Smart TV <em>Samsung</em>",
+		},
+		{
+			name: "markup around and inside the match is escaped",
+			text: model.HighlightedText{
+				Text:    `<script>alert(1)</script> TV <b>"4K"</b> & más`,
+				Matches: []analysis.Span{{Start: 26, End: 28}, {Start: 29, End: 40}},
+			},
+			want: `&lt;script&gt;alert(1)&lt;/script&gt; <em>TV</em> <em>&lt;b&gt;&#34;4K&#34;&lt;/b&gt;</em> &amp; más`,
+		},
+		{
+			name: "img onerror",
+			text: model.HighlightedText{
+				Text:    `Monitor <img src=x onerror=alert(1)>`,
+				Matches: []analysis.Span{{Start: 0, End: 7}},
+			},
+			want: `<em>Monitor</em> &lt;img src=x onerror=alert(1)&gt;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markers.wrap(tt.text); got != tt.want {
+				t.Errorf("wrap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
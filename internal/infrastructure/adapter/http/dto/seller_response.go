@@ -0,0 +1,33 @@
+package dto
+
+import "meli-product-api/internal/domain/model"
+
+type SellerProductsResponse struct {
+	SellerID     string              `json:"seller_id"`
+	Sort         string              `json:"sort"`
+	TotalResults int                 `json:"total_results"`
+	Limit        int                 `json:"limit"`
+	Offset       int                 `json:"offset"`
+	Results      []ProductSummaryDTO `json:"results"`
+}
+
+func ToSellerResponse(seller *model.Seller) *SellerDTO {
+	dto := toSellerDTO(*seller)
+	return &dto
+}
+
+func ToSellerProductsResponse(sellerID string, products *model.SellerProducts, limit, offset int) *SellerProductsResponse {
+	results := make([]ProductSummaryDTO, len(products.Products))
+	for i, p := range products.Products {
+		results[i] = toProductSummaryDTO(p)
+	}
+
+	return &SellerProductsResponse{
+		SellerID:     sellerID,
+		Sort:         string(products.Sort),
+		TotalResults: products.Total,
+		Limit:        limit,
+		Offset:       offset,
+		Results:      results,
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"meli-product-api/internal/infrastructure/resilience"
+	"net/http"
+)
+
+type CircuitBreakerHandler struct {
+	breakers []*resilience.CircuitBreaker
+	logger   *slog.Logger
+}
+
+func NewCircuitBreakerHandler(
+	breakers []*resilience.CircuitBreaker,
+	logger *slog.Logger,
+) *CircuitBreakerHandler {
+	return &CircuitBreakerHandler{
+		breakers: breakers,
+		logger:   logger,
+	}
+}
+
+// ListCircuitBreakers godoc
+// @Summary Circuit breaker state of each downstream service
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.CircuitBreakersResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/admin/circuit-breakers [get]
+func (h *CircuitBreakerHandler) ListCircuitBreakers(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]model.CircuitStatus, len(h.breakers))
+	for i, b := range h.breakers {
+		statuses[i] = b.Status()
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToCircuitBreakersResponse(statuses))
+}
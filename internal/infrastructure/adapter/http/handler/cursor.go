@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"meli-product-api/internal/domain/model"
+	"net/url"
+	"strings"
+)
+
+var errInvalidCursor = errors.New("invalid 'cursor': it is malformed, was tampered with, or belongs to a different search")
+
+// CursorCodec convierte posiciones de búsqueda en tokens opacos firmados con
+// HMAC. Cada token queda atado a los parámetros de la búsqueda que lo emitió,
+// así no puede reutilizarse con otra query, otros filtros u otro orden.
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+type cursorPayload struct {
+	Sort       model.SortOrder `json:"s"`
+	Fuzzy      bool            `json:"f,omitempty"`
+	Key        float64         `json:"k"`
+	ID         string          `json:"id"`
+	Generation uint64          `json:"g,omitempty"`
+	Search     string          `json:"q"`
+}
+
+// Encode devuelve el token para continuar después de cursor en la búsqueda
+// descripta por params.
+func (c *CursorCodec) Encode(cursor model.SearchCursor, params url.Values) string {
+	payload, _ := json.Marshal(cursorPayload{
+		Sort:       cursor.Sort,
+		Fuzzy:      cursor.Fuzzy,
+		Key:        cursor.Key,
+		ID:         cursor.ID,
+		Generation: cursor.Generation,
+		Search:     searchFingerprint(params),
+	})
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload))
+}
+
+// Decode valida la firma del token y que corresponda a params.
+func (c *CursorCodec) Decode(token string, params url.Values) (*model.SearchCursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	mac, err := enc.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+		return nil, errInvalidCursor
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, errInvalidCursor
+	}
+	if p.Search != searchFingerprint(params) {
+		return nil, errInvalidCursor
+	}
+
+	return &model.SearchCursor{
+		Sort:       p.Sort,
+		Fuzzy:      p.Fuzzy,
+		Key:        p.Key,
+		ID:         p.ID,
+		Generation: p.Generation,
+	}, nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// searchFingerprint resume los parámetros que definen el conjunto y el
+// orden de los resultados; los de paginación y presentación no cuentan.
+func searchFingerprint(params url.Values) string {
+	search := make(url.Values, len(params))
+	for key, values := range params {
+		switch key {
+		case "cursor", "limit", "offset", "debug", "highlight", "histogram_buckets", "histogram_interval":
+			continue
+		}
+		search[key] = values
+	}
+
+	// Encode sorts by key, so parameter order does not matter
+	sum := sha256.Sum256([]byte(search.Encode()))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
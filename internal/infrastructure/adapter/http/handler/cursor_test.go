@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"meli-product-api/internal/domain/model"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec([]byte("test-secret"))
+	cursor := model.SearchCursor{Sort: model.SortRelevance, Fuzzy: true, Key: 1299.99, ID: "MLA123456789", Generation: 7}
+	params := url.Values{"q": {"iphone"}, "brand": {"Apple"}, "sort": {"relevance"}, "limit": {"5"}}
+	token := codec.Encode(cursor, params)
+
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := strings.Replace(mustDecode(t, payload), `"k":1299.99`, `"k":1`, 1)
+
+	tests := []struct {
+		name   string
+		codec  *CursorCodec
+		token  string
+		params url.Values
+		valid  bool
+	}{
+		{name: "round trip", token: token, params: params, valid: true},
+		{
+			name:   "pagination params do not matter",
+			token:  token,
+			params: url.Values{"sort": {"relevance"}, "brand": {"Apple"}, "q": {"iphone"}, "limit": {"20"}, "cursor": {token}, "highlight": {"true"}},
+			valid:  true,
+		},
+		{name: "different query", token: token, params: url.Values{"q": {"samsung"}, "brand": {"Apple"}, "sort": {"relevance"}}},
+		{name: "different filters", token: token, params: url.Values{"q": {"iphone"}, "sort": {"relevance"}}},
+		{name: "tampered payload", token: base64.RawURLEncoding.EncodeToString([]byte(forged)) + "." + signature, params: params},
+		{name: "tampered signature", token: payload + "." + base64.RawURLEncoding.EncodeToString([]byte("not-the-signature")), params: params},
+		{name: "signed with another secret", codec: NewCursorCodec([]byte("other-secret")), token: token, params: params},
+		{name: "missing signature", token: payload, params: params},
+		{name: "not base64", token: "%%%.%%%", params: params},
+		{name: "empty", token: "", params: params},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := codec
+			if tt.codec != nil {
+				c = tt.codec
+			}
+
+			got, err := c.Decode(tt.token, tt.params)
+
+			if !tt.valid {
+				if !errors.Is(err, errInvalidCursor) {
+					t.Fatalf("Decode() err = %v, want errInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() err = %v", err)
+			}
+			if *got != cursor {
+				t.Errorf("Decode() = %+v, want %+v", *got, cursor)
+			}
+		})
+	}
+}
+
+func mustDecode(t *testing.T, s string) string {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
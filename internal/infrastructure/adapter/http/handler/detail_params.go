@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"fmt"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// parseDetailSections lee include=seller,reviews,... Sin el parámetro
+// devuelve nil (todas las secciones); vacío, ninguna.
+func parseDetailSections(params url.Values) (model.DetailSections, error) {
+	if !params.Has("include") {
+		return nil, nil
+	}
+
+	sections := model.DetailSections{}
+	for _, name := range splitList(params.Get("include")) {
+		if !slices.Contains(model.DetailSectionNames, name) {
+			return nil, fmt.Errorf("invalid 'include': must be a comma-separated list of %s", strings.Join(model.DetailSectionNames, ", "))
+		}
+		sections[name] = true
+	}
+	return sections, nil
+}
+
+// parseProductFields lee fields=title,price,... Sin el parámetro devuelve
+// nil (todos los campos).
+func parseProductFields(params url.Values) (map[string]bool, error) {
+	if !params.Has("fields") {
+		return nil, nil
+	}
+
+	fields := map[string]bool{}
+	for _, name := range splitList(params.Get("fields")) {
+		if !slices.Contains(dto.ProductFields, name) {
+			return nil, fmt.Errorf("invalid 'fields': must be a comma-separated list of %s", strings.Join(dto.ProductFields, ", "))
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// splitList separa una lista por comas, sin espacios ni elementos vacíos.
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
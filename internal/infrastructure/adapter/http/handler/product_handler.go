@@ -1,9 +1,11 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"meli-product-api/internal/application/service"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/query"
 	"meli-product-api/internal/infrastructure/adapter/http/dto"
 	"net/http"
 	"strconv"
@@ -16,29 +18,41 @@ import (
 type ProductHandler struct {
 	aggregatorService *service.ProductAggregatorService
 	searchService     *service.ProductSearchService
+	suggestService    *service.ProductSuggestService
+	cursors           *CursorCodec
+	highlight         dto.HighlightMarkers
 	logger            *slog.Logger
 }
 
 func NewProductHandler(
 	aggregatorService *service.ProductAggregatorService,
 	searchService *service.ProductSearchService,
+	suggestService *service.ProductSuggestService,
+	cursors *CursorCodec,
+	highlight dto.HighlightMarkers,
 	logger *slog.Logger,
 ) *ProductHandler {
 	return &ProductHandler{
 		aggregatorService: aggregatorService,
 		searchService:     searchService,
+		suggestService:    suggestService,
+		cursors:           cursors,
+		highlight:         highlight,
 		logger:            logger,
 	}
 }
 
 // GetProductDetails godoc
 // @Summary Get product details
-// @Description Get complete product details with seller, reviews, questions, and related products
+// @Description Get complete product details with seller, reviews, questions, and related products. Sections that fail or time out are returned empty and listed in degraded_sections
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param include query string false "Comma-separated sections to fetch: seller, reviews, questions, related (default all; empty for none)"
+// @Param fields query string false "Comma-separated product fields to return, e.g. title,price,images (id is always included)"
 // @Success 200 {object} dto.ProductDetailsResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/products/{id} [get]
@@ -53,10 +67,22 @@ func (h *ProductHandler) GetProductDetails(w http.ResponseWriter, r *http.Reques
 		"remote_addr", r.RemoteAddr,
 	)
 
+	sections, err := parseDetailSections(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	fields, err := parseProductFields(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
 	start := time.Now()
 
 	// Call service
-	details, err := h.aggregatorService.GetProductDetails(ctx, productID)
+	details, err := h.aggregatorService.GetProductDetails(ctx, productID, sections)
 	if err != nil {
 		if err == service.ErrProductNotFound {
 			h.respondError(w, http.StatusNotFound, "Product not found with ID: "+productID, r.URL.Path)
@@ -67,7 +93,7 @@ func (h *ProductHandler) GetProductDetails(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Map to DTO
-	response := dto.ToProductDetailsResponse(details)
+	response := dto.ToProductDetailsResponse(details, sections, fields)
 
 	duration := time.Since(start)
 	h.logger.Info("HTTP 200 OK",
@@ -80,13 +106,30 @@ func (h *ProductHandler) GetProductDetails(w http.ResponseWriter, r *http.Reques
 
 // SearchProducts godoc
 // @Summary Search products
-// @Description Search products by keyword with pagination
+// @Description Search products by keyword and structured filters with pagination and facet counts. q may be omitted to browse a category, brand or seller.
 // @Tags products
 // @Accept json
 // @Produce json
-// @Param q query string true "Search keyword"
+// @Param q query string false "Search query: words, \"phrases\", OR, -exclusions, (groups) and field:value qualifiers"
+// @Param price_min query number false "Minimum price" minimum(0)
+// @Param price_max query number false "Maximum price" minimum(0)
+// @Param condition query string false "Condition" Enums(new, used)
+// @Param brand query string false "Brand"
+// @Param category query string false "Category"
+// @Param seller query string false "Seller ID"
+// @Param in_stock query bool false "Only products with available quantity"
+// @Param free_shipping query bool false "Free shipping"
+// @Param attr[Name] query string false "Attribute value, e.g. attr[Color]=Negro (repeat to accept several values)"
+// @Param sort query string false "Sort order" Enums(relevance, price_asc, price_desc, best_selling, newest, biggest_discount) default(relevance)
+// @Param group_by query string false "Collapse listings of the same brand and model into one result with variant count and price range" Enums(model)
+// @Param skip_interpretation query string false "Comma-separated kinds not to infer from q: price, condition, brand"
+// @Param debug query bool false "Include relevance score in each result"
+// @Param highlight query bool false "Mark matched terms in the title and a description snippet"
+// @Param histogram_buckets query int false "Number of price histogram buckets, with rounded boundaries" minimum(1) maximum(100)
+// @Param histogram_interval query number false "Fixed price histogram bucket width (cannot be combined with histogram_buckets)"
 // @Param limit query int false "Limit" default(10) minimum(1) maximum(50)
 // @Param offset query int false "Offset" default(0) minimum(0)
+// @Param cursor query string false "next_cursor from the previous page (cannot be combined with offset)"
 // @Success 200 {object} dto.ProductSearchResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -95,22 +138,16 @@ func (h *ProductHandler) SearchProducts(w http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 
 	// Parse query parameters
-	query := r.URL.Query().Get("q")
+	keyword := r.URL.Query().Get("q")
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 
 	h.logger.Info("HTTP GET /products/search",
-		"query", query,
+		"query", keyword,
 		"limit", limitStr,
 		"offset", offsetStr,
 	)
 
-	// Validate query parameter
-	if strings.TrimSpace(query) == "" {
-		h.respondError(w, http.StatusBadRequest, "Required parameter 'q' is missing", r.URL.Path)
-		return
-	}
-
 	// Parse and validate limit
 	limit := 10
 	if limitStr != "" {
@@ -133,29 +170,166 @@ func (h *ProductHandler) SearchProducts(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	filters, err := parseSearchFilters(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	// Without q the request browses a category or brand listing
+	if strings.TrimSpace(keyword) == "" && !filters.Scoped() {
+		h.respondError(w, http.StatusBadRequest, "Required parameter 'q' is missing (or browse with 'category', 'brand' or 'seller')", r.URL.Path)
+		return
+	}
+
+	sortOrder, err := parseSortOrder(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	histogram, err := parseHistogramSpec(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	groupBy, err := parseGroupBy(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	skipInterpretation, err := parseSkipInterpretation(r.URL.Query())
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	debug := false
+	if raw := r.URL.Query().Get("debug"); raw != "" {
+		if debug, err = strconv.ParseBool(raw); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid 'debug': must be true or false", r.URL.Path)
+			return
+		}
+	}
+
+	highlight := false
+	if raw := r.URL.Query().Get("highlight"); raw != "" {
+		if highlight, err = strconv.ParseBool(raw); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid 'highlight': must be true or false", r.URL.Path)
+			return
+		}
+	}
+
+	// A cursor continues a previous page and replaces offset
+	page := model.Page{Limit: limit, Offset: offset}
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		if offset > 0 {
+			h.respondError(w, http.StatusBadRequest, "'cursor' and 'offset' cannot be combined", r.URL.Path)
+			return
+		}
+		if page.After, err = h.cursors.Decode(token, r.URL.Query()); err != nil {
+			h.respondError(w, http.StatusBadRequest, err.Error(), r.URL.Path)
+			return
+		}
+	}
+
+	criteria := model.SearchCriteria{
+		Keyword:            keyword,
+		Filters:            filters,
+		Sort:               sortOrder,
+		Highlight:          highlight,
+		Histogram:          histogram,
+		GroupBy:            groupBy,
+		SkipInterpretation: skipInterpretation,
+	}
+
 	start := time.Now()
 
 	// Call service
-	products, total, err := h.searchService.Search(ctx, query, limit, offset)
+	result, err := h.searchService.Search(ctx, criteria, page)
 	if err != nil {
+		var syntaxErr *query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			h.respondError(w, http.StatusBadRequest, syntaxErr.Error(), r.URL.Path)
+			return
+		}
+		if errors.Is(err, model.ErrHistogramTooFine) {
+			h.respondError(w, http.StatusBadRequest, "invalid 'histogram_interval': "+err.Error(), r.URL.Path)
+			return
+		}
+		if errors.Is(err, service.ErrCursorMismatch) {
+			h.respondError(w, http.StatusBadRequest, errInvalidCursor.Error(), r.URL.Path)
+			return
+		}
+		if errors.Is(err, model.ErrCursorExpired) {
+			h.respondError(w, http.StatusBadRequest, "invalid 'cursor': "+err.Error()+"; start again from the first page", r.URL.Path)
+			return
+		}
 		h.respondError(w, http.StatusInternalServerError, "Error searching products", r.URL.Path)
 		return
 	}
 
 	// Map to DTO
-	response := dto.ToProductSearchResponse(query, products, total, limit, offset)
+	response := dto.ToProductSearchResponse(keyword, result, limit, offset, debug, h.highlight)
+	if result.Next != nil {
+		response.NextCursor = h.cursors.Encode(*result.Next, r.URL.Query())
+	}
 
 	duration := time.Since(start)
 	h.logger.Info("HTTP 200 OK",
-		"query", query,
-		"results", len(products),
-		"total", total,
+		"query", keyword,
+		"results", len(result.Hits),
+		"total", result.Total,
 		"duration_ms", duration.Milliseconds(),
 	)
 
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// SuggestProducts godoc
+// @Summary Autocomplete search terms
+// @Description Top completions over titles, brands, models and categories, weighted by units sold
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param q query string true "Prefix typed by the user"
+// @Param limit query int false "Limit" default(5) minimum(1) maximum(20)
+// @Success 200 {object} dto.SuggestResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/products/suggest [get]
+func (h *ProductHandler) SuggestProducts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query().Get("q")
+	limitStr := r.URL.Query().Get("limit")
+
+	if strings.TrimSpace(query) == "" {
+		h.respondError(w, http.StatusBadRequest, "Required parameter 'q' is missing", r.URL.Path)
+		return
+	}
+
+	limit := 5
+	if limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 20 {
+			h.logger.Warn("Invalid limit, using default", "limit", limitStr)
+			limit = 5
+		}
+	}
+
+	suggestions, err := h.suggestService.Suggest(ctx, query, limit)
+	if err != nil {
+		h.respondError(w, http.StatusInternalServerError, "Error fetching suggestions", r.URL.Path)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, dto.ToSuggestResponse(query, suggestions))
+}
+
 // HealthCheck godoc
 // @Summary Health check
 // @Description Check if the API is running
@@ -172,28 +346,9 @@ func (h *ProductHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 // Helper methods
 func (h *ProductHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.logger.Error("Failed to encode JSON response", "error", err)
-	}
+	respondJSON(h.logger, w, status, data)
 }
 
 func (h *ProductHandler) respondError(w http.ResponseWriter, status int, message string, path string) {
-	errorResponse := dto.ErrorResponse{
-		Timestamp: time.Now(),
-		Status:    status,
-		Error:     http.StatusText(status),
-		Message:   message,
-		Path:      path,
-	}
-
-	h.logger.Warn("HTTP Error",
-		"status", status,
-		"message", message,
-		"path", path,
-	)
-
-	h.respondJSON(w, status, errorResponse)
+	respondError(h.logger, w, status, message, path)
 }
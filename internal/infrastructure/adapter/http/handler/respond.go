@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/http"
+	"time"
+)
+
+// respondJSON y respondError son compartidos por todos los handlers.
+func respondJSON(logger *slog.Logger, w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logger.Error("Failed to encode JSON response", "error", err)
+	}
+}
+
+func respondError(logger *slog.Logger, w http.ResponseWriter, status int, message string, path string) {
+	errorResponse := dto.ErrorResponse{
+		Timestamp: time.Now(),
+		Status:    status,
+		Error:     http.StatusText(status),
+		Message:   message,
+		Path:      path,
+	}
+
+	logger.Warn("HTTP Error",
+		"status", status,
+		"message", message,
+		"path", path,
+	)
+
+	respondJSON(logger, w, status, errorResponse)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"meli-product-api/internal/application/service"
+	"meli-product-api/internal/domain/port"
+	"meli-product-api/internal/domain/query"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// UserIDHeader identifica al usuario dueño de las búsquedas guardadas.
+const UserIDHeader = "X-User-ID"
+
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 200
+	maxSavedSearchBody        = 64 << 10
+)
+
+// savedSearchFilters son los filtros de /products/search que se pueden
+// guardar (además de attr[Nombre]).
+var savedSearchFilters = map[string]bool{
+	"price_min":     true,
+	"price_max":     true,
+	"condition":     true,
+	"brand":         true,
+	"category":      true,
+	"seller":        true,
+	"in_stock":      true,
+	"free_shipping": true,
+}
+
+type SavedSearchHandler struct {
+	savedSearchService *service.SavedSearchService
+	logger             *slog.Logger
+}
+
+func NewSavedSearchHandler(
+	savedSearchService *service.SavedSearchService,
+	logger *slog.Logger,
+) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+		logger:             logger,
+	}
+}
+
+// CreateSavedSearch godoc
+// @Summary Save a search
+// @Description Save a query and filters to be notified when new or updated products match it
+// @Tags saved-searches
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param body body dto.CreateSavedSearchRequest true "Query and filters, with the same names as /products/search"
+// @Success 201 {object} dto.SavedSearchDTO
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/v1/saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateSavedSearchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSavedSearchBody)).Decode(&req); err != nil {
+		respondError(h.logger, w, http.StatusBadRequest, "Invalid JSON body", r.URL.Path)
+		return
+	}
+
+	params, err := req.Params()
+	if err != nil {
+		respondError(h.logger, w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+	for name := range params {
+		if !savedSearchFilters[name] && !strings.HasPrefix(name, "attr[") {
+			respondError(h.logger, w, http.StatusBadRequest, fmt.Sprintf("unknown filter '%s'", name), r.URL.Path)
+			return
+		}
+	}
+	filters, err := parseSearchFilters(params)
+	if err != nil {
+		respondError(h.logger, w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	search, err := h.savedSearchService.Create(r.Context(), userID, req.Name, req.Query, filters)
+	if err != nil {
+		var syntaxErr *query.SyntaxError
+		switch {
+		case errors.As(err, &syntaxErr):
+			respondError(h.logger, w, http.StatusBadRequest, syntaxErr.Error(), r.URL.Path)
+		case errors.Is(err, service.ErrNothingToSearch):
+			respondError(h.logger, w, http.StatusBadRequest, "'q' must have searchable terms, or filters must include 'category', 'brand' or 'seller'", r.URL.Path)
+		case errors.Is(err, port.ErrSavedSearchLimit):
+			respondError(h.logger, w, http.StatusConflict, fmt.Sprintf("A user can save up to %d searches", service.MaxSavedSearchesPerUser), r.URL.Path)
+		default:
+			respondError(h.logger, w, http.StatusInternalServerError, "Error saving search", r.URL.Path)
+		}
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusCreated, dto.ToSavedSearchDTO(*search))
+}
+
+// ListSavedSearches godoc
+// @Summary List saved searches
+// @Tags saved-searches
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} dto.SavedSearchListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/saved-searches [get]
+func (h *SavedSearchHandler) ListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	searches, err := h.savedSearchService.List(r.Context(), userID)
+	if err != nil {
+		respondError(h.logger, w, http.StatusInternalServerError, "Error listing saved searches", r.URL.Path)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToSavedSearchListResponse(searches))
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete a saved search and its notifications
+// @Tags saved-searches
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Saved search ID"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/saved-searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+
+	if err := h.savedSearchService.Delete(r.Context(), userID, id); err != nil {
+		if errors.Is(err, port.ErrSavedSearchNotFound) {
+			respondError(h.logger, w, http.StatusNotFound, "Saved search not found with ID: "+id, r.URL.Path)
+			return
+		}
+		respondError(h.logger, w, http.StatusInternalServerError, "Error deleting saved search", r.URL.Path)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListNotifications godoc
+// @Summary Products that started matching the user's saved searches
+// @Tags saved-searches
+// @Produce json
+// @Param X-User-ID header string true "User ID"
+// @Param limit query int false "Limit" default(50) minimum(1) maximum(200)
+// @Success 200 {object} dto.NotificationListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *SavedSearchHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultNotificationsLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxNotificationsLimit {
+			respondError(h.logger, w, http.StatusBadRequest, fmt.Sprintf("invalid 'limit': must be between 1 and %d", maxNotificationsLimit), r.URL.Path)
+			return
+		}
+	}
+
+	notifications, err := h.savedSearchService.Notifications(r.Context(), userID, limit)
+	if err != nil {
+		respondError(h.logger, w, http.StatusInternalServerError, "Error listing notifications", r.URL.Path)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToNotificationListResponse(notifications))
+}
+
+// userID lee el header de usuario; si falta ya respondió 400.
+func (h *SavedSearchHandler) userID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := strings.TrimSpace(r.Header.Get(UserIDHeader))
+	if userID == "" {
+		respondError(h.logger, w, http.StatusBadRequest, "Required header '"+UserIDHeader+"' is missing", r.URL.Path)
+		return "", false
+	}
+	return userID, true
+}
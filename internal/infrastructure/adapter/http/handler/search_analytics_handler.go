@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"meli-product-api/internal/application/service"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAnalyticsWindow = 24 * time.Hour
+	defaultAnalyticsLimit  = 20
+	maxAnalyticsLimit      = 100
+)
+
+type SearchAnalyticsHandler struct {
+	analyticsService *service.SearchAnalyticsService
+	// maxWindow es la retención del almacén; ventanas más largas no
+	// tendrían datos.
+	maxWindow time.Duration
+	logger    *slog.Logger
+}
+
+func NewSearchAnalyticsHandler(
+	analyticsService *service.SearchAnalyticsService,
+	maxWindow time.Duration,
+	logger *slog.Logger,
+) *SearchAnalyticsHandler {
+	return &SearchAnalyticsHandler{
+		analyticsService: analyticsService,
+		maxWindow:        maxWindow,
+		logger:           logger,
+	}
+}
+
+// TopQueries godoc
+// @Summary Most searched queries
+// @Tags admin
+// @Produce json
+// @Param window query string false "Time window, e.g. 1h, 24h, 7d" default(24h)
+// @Param limit query int false "Limit" default(20) minimum(1) maximum(100)
+// @Success 200 {object} dto.QueryStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/admin/search/top-queries [get]
+func (h *SearchAnalyticsHandler) TopQueries(w http.ResponseWriter, r *http.Request) {
+	window, limit, ok := h.parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.analyticsService.TopQueries(r.Context(), window, limit)
+	if err != nil {
+		respondError(h.logger, w, http.StatusInternalServerError, "Error fetching search analytics", r.URL.Path)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToQueryStatsResponse(window, time.Now().Add(-window), stats))
+}
+
+// ZeroResultQueries godoc
+// @Summary Queries that returned no results
+// @Description Grouped by query and filters, ordered by number of zero-result searches
+// @Tags admin
+// @Produce json
+// @Param window query string false "Time window, e.g. 1h, 24h, 7d" default(24h)
+// @Param limit query int false "Limit" default(20) minimum(1) maximum(100)
+// @Success 200 {object} dto.QueryStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/admin/search/zero-results [get]
+func (h *SearchAnalyticsHandler) ZeroResultQueries(w http.ResponseWriter, r *http.Request) {
+	window, limit, ok := h.parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.analyticsService.ZeroResultQueries(r.Context(), window, limit)
+	if err != nil {
+		respondError(h.logger, w, http.StatusInternalServerError, "Error fetching search analytics", r.URL.Path)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToQueryStatsResponse(window, time.Now().Add(-window), stats))
+}
+
+// SlowestSearches godoc
+// @Summary Slowest individual searches
+// @Tags admin
+// @Produce json
+// @Param window query string false "Time window, e.g. 1h, 24h, 7d" default(24h)
+// @Param limit query int false "Limit" default(20) minimum(1) maximum(100)
+// @Success 200 {object} dto.SlowSearchesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/admin/search/slowest [get]
+func (h *SearchAnalyticsHandler) SlowestSearches(w http.ResponseWriter, r *http.Request) {
+	window, limit, ok := h.parseReportParams(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := h.analyticsService.SlowestSearches(r.Context(), window, limit)
+	if err != nil {
+		respondError(h.logger, w, http.StatusInternalServerError, "Error fetching search analytics", r.URL.Path)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToSlowSearchesResponse(window, time.Now().Add(-window), events))
+}
+
+// parseReportParams valida window y limit; si falla ya respondió 400.
+func (h *SearchAnalyticsHandler) parseReportParams(w http.ResponseWriter, r *http.Request) (time.Duration, int, bool) {
+	params := r.URL.Query()
+
+	window, err := parseWindow(params, h.maxWindow)
+	if err != nil {
+		respondError(h.logger, w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return 0, 0, false
+	}
+
+	limit := defaultAnalyticsLimit
+	if raw := params.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxAnalyticsLimit {
+			respondError(h.logger, w, http.StatusBadRequest, fmt.Sprintf("invalid 'limit': must be between 1 and %d", maxAnalyticsLimit), r.URL.Path)
+			return 0, 0, false
+		}
+	}
+
+	return window, limit, true
+}
+
+// parseWindow acepta duraciones de Go ("90m", "24h") y días ("7d").
+func parseWindow(params url.Values, max time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(params.Get("window"))
+	if raw == "" {
+		return min(defaultAnalyticsWindow, max), nil
+	}
+
+	var window time.Duration
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid 'window': %q is not a duration like 1h, 24h or 7d", raw)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if window, err = time.ParseDuration(raw); err != nil {
+			return 0, fmt.Errorf("invalid 'window': %q is not a duration like 1h, 24h or 7d", raw)
+		}
+	}
+
+	if window <= 0 || window > max {
+		return 0, fmt.Errorf("invalid 'window': must be positive and at most %s", max)
+	}
+	return window, nil
+}
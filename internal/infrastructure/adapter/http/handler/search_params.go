@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"meli-product-api/internal/domain/model"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var validConditions = map[string]bool{
+	"new":  true,
+	"used": true,
+}
+
+// parseSearchFilters valida los filtros estructurados de /products/search.
+// Los errores devueltos se muestran tal cual al cliente en un 400.
+func parseSearchFilters(params url.Values) (model.SearchFilters, error) {
+	var filters model.SearchFilters
+	var err error
+
+	if filters.PriceMin, err = parsePrice(params, "price_min"); err != nil {
+		return filters, err
+	}
+	if filters.PriceMax, err = parsePrice(params, "price_max"); err != nil {
+		return filters, err
+	}
+	if filters.PriceMin != nil && filters.PriceMax != nil && *filters.PriceMin > *filters.PriceMax {
+		return filters, errors.New("'price_min' must be less than or equal to 'price_max'")
+	}
+
+	if condition := strings.TrimSpace(params.Get("condition")); condition != "" {
+		condition = strings.ToLower(condition)
+		if !validConditions[condition] {
+			return filters, errors.New("invalid 'condition': must be one of new, used")
+		}
+		filters.Condition = condition
+	}
+
+	filters.Brand = strings.TrimSpace(params.Get("brand"))
+	filters.Category = strings.TrimSpace(params.Get("category"))
+	filters.Seller = strings.TrimSpace(params.Get("seller"))
+
+	if raw := params.Get("in_stock"); raw != "" {
+		inStock, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filters, errors.New("invalid 'in_stock': must be true or false")
+		}
+		filters.InStock = inStock
+	}
+
+	if raw := params.Get("free_shipping"); raw != "" {
+		freeShipping, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filters, errors.New("invalid 'free_shipping': must be true or false")
+		}
+		filters.FreeShipping = &freeShipping
+	}
+
+	if filters.Attributes, err = parseAttributeFilters(params); err != nil {
+		return filters, err
+	}
+
+	return filters, nil
+}
+
+// parseAttributeFilters lee los parámetros attr[Nombre]=Valor. Repetir el
+// parámetro acepta cualquiera de los valores.
+func parseAttributeFilters(params url.Values) (map[string][]string, error) {
+	var attributes map[string][]string
+
+	for key, values := range params {
+		name, ok := strings.CutPrefix(key, "attr[")
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutSuffix(name, "]")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			return nil, fmt.Errorf("invalid attribute filter '%s': use attr[Name]=Value", key)
+		}
+
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v == "" {
+				return nil, fmt.Errorf("invalid '%s': value must not be empty", key)
+			}
+			if attributes == nil {
+				attributes = make(map[string][]string)
+			}
+			attributes[name] = append(attributes[name], v)
+		}
+	}
+
+	return attributes, nil
+}
+
+// parseHistogramSpec lee histogram_buckets o histogram_interval (no
+// ambos). Sin ninguno devuelve la especificación vacía y rige el default.
+func parseHistogramSpec(params url.Values) (model.HistogramSpec, error) {
+	var spec model.HistogramSpec
+
+	rawBuckets, rawInterval := params.Get("histogram_buckets"), params.Get("histogram_interval")
+	if rawBuckets != "" && rawInterval != "" {
+		return spec, errors.New("'histogram_buckets' and 'histogram_interval' cannot be combined")
+	}
+
+	if rawBuckets != "" {
+		buckets, err := strconv.Atoi(rawBuckets)
+		if err != nil || buckets < 1 || buckets > model.MaxHistogramBuckets {
+			return spec, fmt.Errorf("invalid 'histogram_buckets': must be between 1 and %d", model.MaxHistogramBuckets)
+		}
+		spec.Buckets = buckets
+	}
+
+	if rawInterval != "" {
+		interval, err := strconv.ParseFloat(rawInterval, 64)
+		if err != nil || !isFinite(interval) || interval <= 0 {
+			return spec, errors.New("invalid 'histogram_interval': must be a positive number")
+		}
+		spec.Interval = interval
+	}
+
+	return spec, nil
+}
+
+// parseSkipInterpretation lee skip_interpretation=price,condition,brand:
+// los tipos de filtro que no deben deducirse del texto de q (el cliente
+// lo envía al quitar un chip).
+func parseSkipInterpretation(params url.Values) (map[string]bool, error) {
+	raw := strings.TrimSpace(params.Get("skip_interpretation"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	valid := make(map[string]bool, len(model.InterpretKinds))
+	for _, kind := range model.InterpretKinds {
+		valid[kind] = true
+	}
+
+	skip := make(map[string]bool)
+	for _, kind := range strings.Split(raw, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if !valid[kind] {
+			return nil, fmt.Errorf("invalid 'skip_interpretation': must be a comma-separated list of %s", strings.Join(model.InterpretKinds, ", "))
+		}
+		skip[kind] = true
+	}
+
+	return skip, nil
+}
+
+func parseGroupBy(params url.Values) (model.GroupBy, error) {
+	switch raw := strings.ToLower(strings.TrimSpace(params.Get("group_by"))); raw {
+	case "":
+		return model.GroupNone, nil
+	case string(model.GroupByModel):
+		return model.GroupByModel, nil
+	default:
+		return "", errors.New("invalid 'group_by': must be model")
+	}
+}
+
+func parseSortOrder(params url.Values) (model.SortOrder, error) {
+	raw := strings.TrimSpace(params.Get("sort"))
+	if raw == "" {
+		return model.SortRelevance, nil
+	}
+
+	order := model.SortOrder(strings.ToLower(raw))
+	if !order.IsValid() {
+		valid := make([]string, len(model.SortOrders))
+		for i, o := range model.SortOrders {
+			valid[i] = string(o)
+		}
+		return "", fmt.Errorf("invalid 'sort': must be one of %s", strings.Join(valid, ", "))
+	}
+
+	return order, nil
+}
+
+func parsePrice(params url.Values, name string) (*float64, error) {
+	raw := params.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || !isFinite(value) || value < 0 {
+		return nil, fmt.Errorf("invalid '%s': must be a non-negative number", name)
+	}
+
+	return &value, nil
+}
+
+// isFinite descarta NaN e infinitos, que ParseFloat acepta ("NaN", "Inf").
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseHistogramSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantBuckets  int
+		wantInterval float64
+		wantErr      bool
+	}{
+		{name: "default", query: ""},
+		{name: "buckets", query: "histogram_buckets=8", wantBuckets: 8},
+		{name: "interval", query: "histogram_interval=50000", wantInterval: 50000},
+		{name: "tiny interval is parsed", query: "histogram_interval=1e-300", wantInterval: 1e-300},
+		{name: "both", query: "histogram_buckets=8&histogram_interval=50000", wantErr: true},
+		{name: "too many buckets", query: "histogram_buckets=101", wantErr: true},
+		{name: "zero interval", query: "histogram_interval=0", wantErr: true},
+		{name: "negative interval", query: "histogram_interval=-10", wantErr: true},
+		{name: "NaN interval", query: "histogram_interval=NaN", wantErr: true},
+		{name: "infinite interval", query: "histogram_interval=Inf", wantErr: true},
+		{name: "negative infinite interval", query: "histogram_interval=-Inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, _ := url.ParseQuery(tt.query)
+			spec, err := parseHistogramSpec(params)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHistogramSpec(%q) err = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if !tt.wantErr && (spec.Buckets != tt.wantBuckets || spec.Interval != tt.wantInterval) {
+				t.Errorf("parseHistogramSpec(%q) = %+v", tt.query, spec)
+			}
+		})
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    float64
+		wantNil bool
+		wantErr bool
+	}{
+		{raw: "", wantNil: true},
+		{raw: "0", want: 0},
+		{raw: "1500.50", want: 1500.50},
+		{raw: "-1", wantErr: true},
+		{raw: "abc", wantErr: true},
+		{raw: "NaN", wantErr: true},
+		{raw: "Inf", wantErr: true},
+		{raw: "+Inf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parsePrice(url.Values{"price_min": {tt.raw}}, "price_min")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePrice(%q) err = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			switch {
+			case tt.wantErr:
+			case tt.wantNil && got != nil:
+				t.Errorf("parsePrice(%q) = %v, want nil", tt.raw, *got)
+			case !tt.wantNil && (got == nil || *got != tt.want):
+				t.Errorf("parsePrice(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
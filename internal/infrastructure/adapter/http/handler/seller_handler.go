@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"meli-product-api/internal/application/service"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type SellerHandler struct {
+	sellerService *service.SellerService
+	logger        *slog.Logger
+}
+
+func NewSellerHandler(
+	sellerService *service.SellerService,
+	logger *slog.Logger,
+) *SellerHandler {
+	return &SellerHandler{
+		sellerService: sellerService,
+		logger:        logger,
+	}
+}
+
+// GetSeller godoc
+// @Summary Get seller
+// @Description Get seller reputation and sales data
+// @Tags sellers
+// @Produce json
+// @Param id path string true "Seller ID"
+// @Success 200 {object} dto.SellerDTO
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/sellers/{id} [get]
+func (h *SellerHandler) GetSeller(w http.ResponseWriter, r *http.Request) {
+	sellerID := mux.Vars(r)["id"]
+
+	seller, err := h.sellerService.GetSeller(r.Context(), sellerID)
+	if err != nil {
+		h.respondSellerError(w, r, sellerID, err)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToSellerResponse(seller))
+}
+
+// ListSellerProducts godoc
+// @Summary List seller products
+// @Description Paginated listing of the products published by a seller
+// @Tags sellers
+// @Produce json
+// @Param id path string true "Seller ID"
+// @Param sort query string false "Sort order (relevance lists best sellers first)" Enums(relevance, price_asc, price_desc, best_selling, newest, biggest_discount) default(best_selling)
+// @Param limit query int false "Limit" default(10) minimum(1) maximum(50)
+// @Param offset query int false "Offset" default(0) minimum(0)
+// @Success 200 {object} dto.SellerProductsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/v1/sellers/{id}/products [get]
+func (h *SellerHandler) ListSellerProducts(w http.ResponseWriter, r *http.Request) {
+	sellerID := mux.Vars(r)["id"]
+	limitStr := r.URL.Query().Get("limit")
+	offsetStr := r.URL.Query().Get("offset")
+
+	limit := 10
+	if limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 50 {
+			h.logger.Warn("Invalid limit, using default", "limit", limitStr)
+			limit = 10
+		}
+	}
+
+	offset := 0
+	if offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			h.logger.Warn("Invalid offset, using default", "offset", offsetStr)
+			offset = 0
+		}
+	}
+
+	sortOrder, err := parseSortOrder(r.URL.Query())
+	if err != nil {
+		respondError(h.logger, w, http.StatusBadRequest, err.Error(), r.URL.Path)
+		return
+	}
+
+	products, err := h.sellerService.ListProducts(r.Context(), sellerID, sortOrder, model.Page{Limit: limit, Offset: offset})
+	if err != nil {
+		h.respondSellerError(w, r, sellerID, err)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.ToSellerProductsResponse(sellerID, products, limit, offset))
+}
+
+func (h *SellerHandler) respondSellerError(w http.ResponseWriter, r *http.Request, sellerID string, err error) {
+	if errors.Is(err, port.ErrSellerNotFound) {
+		respondError(h.logger, w, http.StatusNotFound, "Seller not found with ID: "+sellerID, r.URL.Path)
+		return
+	}
+	if errors.Is(err, port.ErrCircuitOpen) {
+		respondError(h.logger, w, http.StatusServiceUnavailable, "Seller service is temporarily unavailable", r.URL.Path)
+		return
+	}
+	respondError(h.logger, w, http.StatusInternalServerError, "Internal server error", r.URL.Path)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// AdminAuth exige "Authorization: Bearer <token>" en las rutas de
+// administración. Con token vacío las rechaza todas con 503: nunca quedan
+// abiertas por omisión.
+func AdminAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				respondError(w, http.StatusServiceUnavailable, "Admin endpoints are disabled: ADMIN_TOKEN is not set", r.URL.Path)
+			})
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				respondError(w, http.StatusUnauthorized, "Missing or invalid admin token", r.URL.Path)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "no token configured", token: "", header: "", want: http.StatusServiceUnavailable},
+		{name: "no token configured ignores header", token: "", header: "Bearer ", want: http.StatusServiceUnavailable},
+		{name: "missing header", token: "secret", header: "", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "secret", header: "Basic secret", want: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", header: "Bearer secret", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/circuit-breakers", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			AdminAuth(tt.token)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK {
+				return
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			var body dto.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("body is not an error response: %v", err)
+			}
+			if body.Status != tt.want || body.Path != req.URL.Path || body.Message == "" {
+				t.Errorf("body = %+v, want status %d and path %s", body, tt.want, req.URL.Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"encoding/json"
+	"meli-product-api/internal/infrastructure/adapter/http/dto"
+	"net/http"
+	"time"
+)
+
+// respondError responde con el mismo cuerpo JSON que los handlers. El log
+// del status lo deja el middleware Logger.
+func respondError(w http.ResponseWriter, status int, message string, path string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(dto.ErrorResponse{
+		Timestamp: time.Now(),
+		Status:    status,
+		Error:     http.StatusText(status),
+		Message:   message,
+		Path:      path,
+	})
+}
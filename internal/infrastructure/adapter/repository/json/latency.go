@@ -0,0 +1,20 @@
+package json
+
+import (
+	"context"
+	"time"
+)
+
+// simulateLatency imita la demora de red de un microservicio; corta antes
+// si ctx se cancela o vence.
+func simulateLatency(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package json
+
+import (
+	"math"
+	"meli-product-api/internal/domain/model"
+)
+
+// niceSteps son los múltiplos de potencias de 10 que se usan como ancho de
+// intervalo, así los cortes quedan en valores redondos (50.000, 100.000...).
+var niceSteps = []float64{1, 2, 2.5, 5}
+
+// buildPriceHistogram reparte prices según spec. Devuelve nil si no hay
+// precios.
+func buildPriceHistogram(prices []float64, spec model.HistogramSpec) (*model.PriceHistogram, error) {
+	if len(prices) == 0 {
+		return nil, nil
+	}
+
+	lo, hi := prices[0], prices[0]
+	for _, p := range prices[1:] {
+		lo = math.Min(lo, p)
+		hi = math.Max(hi, p)
+	}
+
+	width := spec.Interval
+	if width <= 0 {
+		width = niceWidth(lo, hi, spec.Buckets)
+	}
+	start := math.Floor(lo/width) * width
+	// Counted as a float so a tiny width cannot overflow int; the negated
+	// comparison also rejects NaN
+	count := math.Floor((hi-start)/width) + 1
+	if !(count <= model.MaxHistogramBuckets) {
+		return nil, model.ErrHistogramTooFine
+	}
+
+	buckets := make([]model.HistogramBucket, int(count))
+	for i := range buckets {
+		buckets[i].Min = start + float64(i)*width
+		buckets[i].Max = start + float64(i+1)*width
+	}
+	for _, p := range prices {
+		buckets[bucketIndex(p, start, width)].Count++
+	}
+
+	return &model.PriceHistogram{Min: lo, Max: hi, Buckets: buckets}, nil
+}
+
+// niceWidth es el menor ancho redondo con el que [lo, hi] entra en n
+// intervalos alineados a ese ancho.
+func niceWidth(lo, hi float64, n int) float64 {
+	n = max(n, 1)
+	span := hi - lo
+	if span == 0 {
+		// A single price still gets a bucket around it
+		span = math.Max(math.Abs(hi), 1)
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(span/float64(n))))
+	for {
+		for _, step := range niceSteps {
+			width := step * magnitude
+			start := math.Floor(lo/width) * width
+			if bucketIndex(hi, start, width) < n {
+				return width
+			}
+		}
+		magnitude *= 10
+	}
+}
+
+// bucketIndex ubica price; el tope de un intervalo pertenece al siguiente.
+func bucketIndex(price, start, width float64) int {
+	return int(math.Floor((price - start) / width))
+}
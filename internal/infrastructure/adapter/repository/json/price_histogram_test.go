@@ -0,0 +1,55 @@
+package json
+
+import (
+	"errors"
+	"math"
+	"meli-product-api/internal/domain/model"
+	"testing"
+)
+
+func TestBuildPriceHistogram(t *testing.T) {
+	prices := []float64{120000, 180000, 250000, 990000}
+
+	tests := []struct {
+		name        string
+		prices      []float64
+		spec        model.HistogramSpec
+		wantBuckets int
+		wantErr     error
+	}{
+		{name: "no prices", spec: model.HistogramSpec{Buckets: 5}},
+		{name: "fixed interval", prices: prices, spec: model.HistogramSpec{Interval: 250000}, wantBuckets: 4},
+		{name: "single price", prices: []float64{5000}, spec: model.HistogramSpec{Buckets: 5}, wantBuckets: 1},
+		{name: "too many buckets", prices: prices, spec: model.HistogramSpec{Interval: 1000}, wantErr: model.ErrHistogramTooFine},
+		{name: "tiny interval", prices: prices, spec: model.HistogramSpec{Interval: 1e-300}, wantErr: model.ErrHistogramTooFine},
+		{name: "NaN interval", prices: prices, spec: model.HistogramSpec{Interval: math.NaN()}, wantErr: model.ErrHistogramTooFine},
+		{name: "infinite interval", prices: prices, spec: model.HistogramSpec{Interval: math.Inf(1)}, wantErr: model.ErrHistogramTooFine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			histogram, err := buildPriceHistogram(tt.prices, tt.spec)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("buildPriceHistogram() err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil || tt.wantBuckets == 0 {
+				if histogram != nil {
+					t.Errorf("buildPriceHistogram() = %+v, want nil", histogram)
+				}
+				return
+			}
+
+			if len(histogram.Buckets) != tt.wantBuckets {
+				t.Fatalf("buckets = %d, want %d", len(histogram.Buckets), tt.wantBuckets)
+			}
+			total := 0
+			for _, b := range histogram.Buckets {
+				total += b.Count
+			}
+			if total != len(tt.prices) {
+				t.Errorf("bucket counts add up to %d, want %d", total, len(tt.prices))
+			}
+		})
+	}
+}
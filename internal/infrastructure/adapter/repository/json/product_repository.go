@@ -4,22 +4,53 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"meli-product-api/internal/domain/analysis"
 	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/query"
+	"meli-product-api/internal/infrastructure/search"
 	"os"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
+// priceFacetBounds define los cortes de los rangos de precio del facet.
+var priceFacetBounds = []float64{100000, 300000, 600000}
+
+// snippetLength es el largo máximo, en caracteres, del fragmento de
+// descripción que acompaña al resaltado.
+const snippetLength = 160
+
 type ProductRepository struct {
 	mu       sync.RWMutex
-	products []model.Product
+	products []*model.Product // por ordinal del índice; nil si fue eliminado
+	index    *search.Index
+	words    *search.Dictionary
+	suggest  *search.Suggester
+	brands   map[string]int // productos por marca
+	scorer   *search.Scorer
+	analyzer *analysis.Analyzer
 	filePath string
+	modTime  time.Time
+	// generation cambia con cada alta, baja o modificación de un producto
+	generation uint64
+	// listeners reciben los cambios después de aplicarlos, sin el lock
+	listeners []func([]model.ProductChange)
 }
 
-func NewProductRepository(filePath string) (*ProductRepository, error) {
+func NewProductRepository(filePath string, analyzer *analysis.Analyzer, scorer *search.Scorer) (*ProductRepository, error) {
 	repo := &ProductRepository{
 		filePath: filePath,
-		products: make([]model.Product, 0),
+		products: make([]*model.Product, 0),
+		index:    search.NewIndex(analyzer),
+		words:    search.NewDictionary(),
+		suggest:  search.NewSuggester(analyzer),
+		brands:   make(map[string]int),
+		scorer:   scorer,
+		analyzer: analyzer,
 	}
 
 	if err := repo.load(); err != nil {
@@ -30,68 +61,493 @@ func NewProductRepository(filePath string) (*ProductRepository, error) {
 }
 
 func (r *ProductRepository) load() error {
-	data, err := os.ReadFile(r.filePath)
+	info, err := os.Stat(r.filePath)
 	if err != nil {
 		return err
 	}
+	products, err := r.readFile()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range products {
+		r.put(p)
+	}
+	r.suggest.Flush()
+	r.modTime = info.ModTime()
+
+	return nil
+}
+
+func (r *ProductRepository) readFile() ([]model.Product, error) {
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []model.Product
+	if err := json.Unmarshal(data, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Reload vuelve a leer el archivo si cambió desde la última carga: agrega
+// o actualiza los productos que cambiaron y elimina los que ya no están.
+// Reporta si releyó el archivo. Si es inválido se conserva el catálogo.
+func (r *ProductRepository) Reload() (bool, error) {
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	if !info.ModTime().After(r.modTime) {
+		r.mu.Unlock()
+		return false, nil
+	}
+	// Remember the attempt so a broken file is reported once, not on
+	// every poll
+	r.modTime = info.ModTime()
+
+	products, err := r.readFile()
+	if err != nil {
+		r.mu.Unlock()
+		return false, err
+	}
+
+	var changes []model.ProductChange
+	inFile := make(map[string]bool, len(products))
+	for _, p := range products {
+		inFile[p.ID] = true
+		kind := model.ProductAdded
+		if ord, ok := r.index.Ordinal(p.ID); ok {
+			if reflect.DeepEqual(*r.products[ord], p) {
+				continue
+			}
+			kind = model.ProductUpdated
+		}
+		r.put(p)
+		changes = append(changes, model.ProductChange{ProductID: p.ID, Kind: kind})
+	}
+	for _, p := range r.products {
+		if p != nil && !inFile[p.ID] {
+			r.remove(p.ID)
+			changes = append(changes, model.ProductChange{ProductID: p.ID, Kind: model.ProductRemoved})
+		}
+	}
+	r.suggest.Flush()
+	r.mu.Unlock()
+
+	r.notify(changes)
+	return true, nil
+}
+
+// OnChange registra fn para recibir los productos agregados, modificados o
+// eliminados.
+func (r *ProductRepository) OnChange(fn func([]model.ProductChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.listeners = append(r.listeners, fn)
+}
+
+func (r *ProductRepository) notify(changes []model.ProductChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	r.mu.RLock()
+	listeners := r.listeners
+	r.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(changes)
+	}
+}
+
+// Save agrega o reemplaza un producto y actualiza el índice. Los cambios
+// viven en memoria hasta que el archivo JSON se modifique y se recargue.
+func (r *ProductRepository) Save(ctx context.Context, product model.Product) error {
+	if product.ID == "" {
+		return errors.New("product id is required")
+	}
+
+	r.mu.Lock()
+	kind := model.ProductAdded
+	if _, ok := r.index.Ordinal(product.ID); ok {
+		kind = model.ProductUpdated
+	}
+	r.put(product)
+	r.suggest.Flush()
+	r.mu.Unlock()
+
+	r.notify([]model.ProductChange{{ProductID: product.ID, Kind: kind}})
+	return nil
+}
+
+// Delete elimina el producto del catálogo y del índice, igual que si
+// hubiera desaparecido del archivo en un Reload.
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	if !r.remove(id) {
+		r.mu.Unlock()
+		return errors.New("product not found")
+	}
+	r.suggest.Flush()
+	r.mu.Unlock()
+
+	r.notify([]model.ProductChange{{ProductID: id, Kind: model.ProductRemoved}})
+	return nil
+}
+
+// remove debe llamarse con el lock de escritura tomado.
+func (r *ProductRepository) remove(id string) bool {
+	ord, ok := r.index.Ordinal(id)
+	if !ok {
+		return false
+	}
+
+	r.index.Remove(id)
+	r.unindexExtras(*r.products[ord])
+	r.products[ord] = nil
+	r.generation++
+	return true
+}
+
+// put debe llamarse con el lock de escritura tomado (o durante la carga).
+// products sigue los ordinales del índice: un producto actualizado
+// conserva su lugar y uno nuevo ocupa el de uno eliminado o se agrega al
+// final.
+func (r *ProductRepository) put(p model.Product) {
+	if ord, ok := r.index.Ordinal(p.ID); ok {
+		r.unindexExtras(*r.products[ord])
+	}
 
-	return json.Unmarshal(data, &r.products)
+	ord := r.index.Add(toDocument(p))
+	r.words.Add(r.dictionaryWords(p))
+	for kind, text := range completions(p) {
+		r.suggest.Add(kind, text, p.SoldQuantity)
+	}
+	if p.Brand != "" {
+		r.brands[p.Brand]++
+	}
+	if ord == len(r.products) {
+		r.products = append(r.products, &p)
+	} else {
+		r.products[ord] = &p
+	}
+	r.generation++
+}
+
+// unindexExtras quita el producto del diccionario ortográfico, del
+// autocompletado y del conteo de marcas (el índice principal se actualiza
+// aparte).
+func (r *ProductRepository) unindexExtras(p model.Product) {
+	r.words.Remove(r.dictionaryWords(p))
+	for kind, text := range completions(p) {
+		r.suggest.Remove(kind, text, p.SoldQuantity)
+	}
+	if r.brands[p.Brand]--; r.brands[p.Brand] <= 0 {
+		delete(r.brands, p.Brand)
+	}
 }
 
 func (r *ProductRepository) FindByID(ctx context.Context, id string) (*model.Product, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	for _, p := range r.products {
-		if p.ID == id {
-			return &p, nil
-		}
+	ord, ok := r.index.Ordinal(id)
+	if !ok {
+		return nil, errors.New("product not found")
 	}
 
-	return nil, errors.New("product not found")
+	p := *r.products[ord]
+	return &p, nil
 }
 
-func (r *ProductRepository) Search(ctx context.Context, keyword string, limit, offset int) ([]model.Product, error) {
+// Search evalúa la búsqueda una sola vez y de ahí saca la página, los
+// totales y, si se piden, facets e histograma, así todo corresponde al
+// mismo catálogo aunque cambie en el medio. Devuelve
+// model.ErrCursorExpired si page.After es un cursor de relevancia de otra
+// generación del catálogo.
+func (r *ProductRepository) Search(ctx context.Context, criteria model.SearchCriteria, page model.Page) (*model.SearchPage, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	keyword = strings.ToLower(keyword)
-	var results []model.Product
+	if page.After != nil && page.After.Expired(r.generation) {
+		return nil, model.ErrCursorExpired
+	}
 
-	for _, p := range r.products {
-		if r.matches(p, keyword) {
-			results = append(results, p)
+	docs := r.match(criteria)
+	result := &model.SearchPage{Total: len(docs)}
+	if criteria.Facets {
+		result.Facets = r.facets(docs)
+
+		var err error
+		if result.PriceHistogram, err = r.priceHistogram(docs, criteria.Histogram); err != nil {
+			return nil, err
 		}
 	}
 
+	terms := query.PositiveTerms(criteria.Query)
+	scores := r.scorer.Score(r.index, terms, docs)
+
+	var exact map[int]bool
+	var corrections []string
+	if criteria.Fuzzy {
+		corrections = r.index.Expansions(terms)
+		for i, s := range r.scorer.Score(r.index, corrections, docs) {
+			scores[i] += search.FuzzyPenalty * s
+		}
+
+		exact = make(map[int]bool)
+		for _, ord := range r.index.Evaluate(criteria.Query, false) {
+			exact[ord] = true
+		}
+	}
+
+	hits := make([]model.SearchHit, len(docs))
+	for i, ord := range docs {
+		hits[i] = model.SearchHit{
+			Product:    *r.products[ord],
+			Score:      scores[i],
+			Fuzzy:      criteria.Fuzzy && !exact[ord],
+			Generation: r.generation,
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return criteria.Sort.Compare(hits[i], hits[j]) < 0
+	})
+
+	if criteria.GroupBy == model.GroupByModel {
+		hits = groupByModel(hits)
+		result.Groups = len(hits)
+	}
+
+	// Resuming from a cursor skips everything up to its position, so
+	// products added or removed on earlier pages do not shift this one
+	if page.After != nil {
+		i := sort.Search(len(hits), func(i int) bool { return page.After.Precedes(hits[i]) })
+		hits = hits[i:]
+	}
+
 	// Pagination
-	start := offset
-	end := offset + limit
+	start := page.Offset
+	if page.After != nil {
+		start = 0
+	}
+	end := start + page.Limit
 
-	if start > len(results) {
-		return []model.Product{}, nil
+	if start > len(hits) {
+		result.Hits = []model.SearchHit{}
+		return result, nil
 	}
-	if end > len(results) {
-		end = len(results)
+	if end > len(hits) {
+		end = len(hits)
+	}
+	result.Hits = hits[start:end]
+
+	// Only the returned page is highlighted
+	if criteria.Highlight {
+		matched := make(map[string]bool, len(terms)+len(corrections))
+		for _, term := range terms {
+			matched[term] = true
+		}
+		for _, term := range corrections {
+			matched[term] = true
+		}
+		for i := range result.Hits {
+			result.Hits[i].Highlight = r.highlight(result.Hits[i].Product, matched)
+		}
 	}
 
-	return results[start:end], nil
+	return result, nil
 }
 
-func (r *ProductRepository) Count(ctx context.Context, keyword string) (int, error) {
+// highlight marca los términos encontrados en el título y arma un
+// fragmento de la descripción alrededor de la primera coincidencia.
+func (r *ProductRepository) highlight(p model.Product, terms map[string]bool) *model.Highlight {
+	snippet, matches := analysis.Snippet(p.Description, r.analyzer.Highlight(p.Description, terms), snippetLength)
+
+	return &model.Highlight{
+		Title: model.HighlightedText{
+			Text:    p.Title,
+			Matches: r.analyzer.Highlight(p.Title, terms),
+		},
+		Snippet: model.HighlightedText{
+			Text:    snippet,
+			Matches: matches,
+		},
+	}
+}
+
+// Count cuenta coincidencias o, con criteria.GroupBy, grupos.
+func (r *ProductRepository) Count(ctx context.Context, criteria model.SearchCriteria) (int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	keyword = strings.ToLower(keyword)
-	count := 0
+	docs := r.match(criteria)
+	if criteria.GroupBy != model.GroupByModel {
+		return len(docs), nil
+	}
 
-	for _, p := range r.products {
-		if r.matches(p, keyword) {
-			count++
+	groups := make(map[string]bool)
+	for _, ord := range docs {
+		groups[modelGroupKey(*r.products[ord])] = true
+	}
+	return len(groups), nil
+}
+
+// facets cuenta, por cada faceta, las coincidencias docs.
+func (r *ProductRepository) facets(docs []int) model.SearchFacets {
+	categories := make(map[string]int)
+	brands := make(map[string]int)
+	conditions := make(map[string]int)
+	freeShipping := make(map[string]int)
+	priceCounts := make([]int, len(priceFacetBounds)+1)
+	attributes := make(map[string]map[string]int)
+
+	for _, ord := range docs {
+		p := r.products[ord]
+
+		categories[p.Category]++
+		brands[p.Brand]++
+		conditions[p.Condition]++
+		freeShipping[strconv.FormatBool(p.HasFreeShipping())]++
+		priceCounts[priceBucket(p.Price)]++
+
+		for _, attr := range p.Attributes {
+			if attributes[attr.Name] == nil {
+				attributes[attr.Name] = make(map[string]int)
+			}
+			attributes[attr.Name][attr.Value]++
 		}
 	}
 
-	return count, nil
+	facets := model.SearchFacets{
+		Categories:   toFacetValues(categories),
+		Brands:       toFacetValues(brands),
+		Conditions:   toFacetValues(conditions),
+		FreeShipping: toFacetValues(freeShipping),
+		PriceRanges:  toPriceRangeFacets(priceCounts),
+	}
+	if len(categories) == 1 {
+		facets.Attributes = toAttributeFacets(attributes)
+	}
+
+	return facets
+}
+
+// priceHistogram calcula la distribución de precios de las coincidencias
+// docs; nil si no hay ninguna.
+func (r *ProductRepository) priceHistogram(docs []int, spec model.HistogramSpec) (*model.PriceHistogram, error) {
+	prices := make([]float64, len(docs))
+	for i, ord := range docs {
+		prices[i] = r.products[ord].Price
+	}
+
+	return buildPriceHistogram(prices, spec)
+}
+
+// Matching implementa port.ProductMatcher.
+func (r *ProductRepository) Matching(ctx context.Context, criteria model.SearchCriteria, ids []string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var want map[string]bool
+	if ids != nil {
+		want = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			want[id] = true
+		}
+	}
+
+	var matching []string
+	for _, ord := range r.match(criteria) {
+		if id := r.products[ord].ID; want == nil || want[id] {
+			matching = append(matching, id)
+		}
+	}
+
+	return matching, nil
+}
+
+// Suggest implementa port.ProductSuggester; las sugerencias se ponderan por
+// unidades vendidas.
+func (r *ProductRepository) Suggest(ctx context.Context, prefix string, limit int) ([]model.Suggestion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	completions := r.suggest.Suggest(prefix, limit)
+	suggestions := make([]model.Suggestion, len(completions))
+	for i, c := range completions {
+		suggestions[i] = model.Suggestion{
+			Text:   c.Text,
+			Kind:   c.Kind,
+			Weight: c.Weight,
+		}
+	}
+
+	return suggestions, nil
+}
+
+// Brands implementa port.BrandCatalog.
+func (r *ProductRepository) Brands(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	brands := make([]string, 0, len(r.brands))
+	for brand := range r.brands {
+		brands = append(brands, brand)
+	}
+	sort.Strings(brands)
+
+	return brands, nil
+}
+
+// Correct implementa port.SpellChecker. Una palabra se considera válida si
+// aparece en cualquier campo indexado, aunque no sea parte del diccionario.
+func (r *ProductRepository) Correct(ctx context.Context, words []string) ([][]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	corrections := make([][]string, len(words))
+	for i, word := range words {
+		word = analysis.Fold(word)
+		if r.isIndexed(word) {
+			corrections[i] = []string{word}
+			continue
+		}
+		corrections[i], _ = r.words.Correct(word)
+	}
+
+	return corrections, nil
+}
+
+func (r *ProductRepository) isIndexed(word string) bool {
+	term := r.analyzer.Normalize(word)
+	for _, field := range search.Fields {
+		if len(r.index.Postings(field, term)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// dictionaryWords son las palabras de título, marca y categoría que
+// alimentan las sugerencias ortográficas.
+func (r *ProductRepository) dictionaryWords(p model.Product) []string {
+	var words []string
+	for _, text := range []string{p.Title, p.Brand, p.Category, attributeValues(p)} {
+		for _, tok := range r.analyzer.Tokenize(text) {
+			if len(tok.Term) >= 2 && !r.analyzer.IsStopword(tok.Term) {
+				words = append(words, tok.Term)
+			}
+		}
+	}
+	return words
 }
 
 func (r *ProductRepository) FindRelated(ctx context.Context, productID, category string, limit int) ([]model.Product, error) {
@@ -101,8 +557,8 @@ func (r *ProductRepository) FindRelated(ctx context.Context, productID, category
 	var results []model.Product
 
 	for _, p := range r.products {
-		if p.ID != productID && p.Category == category {
-			results = append(results, p)
+		if p != nil && p.ID != productID && p.Category == category {
+			results = append(results, *p)
 			if len(results) >= limit {
 				break
 			}
@@ -112,14 +568,170 @@ func (r *ProductRepository) FindRelated(ctx context.Context, productID, category
 	return results, nil
 }
 
-func (r *ProductRepository) matches(p model.Product, keyword string) bool {
-	title := strings.ToLower(p.Title)
-	desc := strings.ToLower(p.Description)
-	category := strings.ToLower(p.Category)
-	brand := strings.ToLower(p.Brand)
+// match evalúa la query contra el índice y aplica los filtros sobre los
+// candidatos; sin query los candidatos son todo el catálogo. Devuelve
+// ordinales en orden creciente.
+func (r *ProductRepository) match(criteria model.SearchCriteria) []int {
+	var candidates []int
+	if criteria.Query != nil {
+		candidates = r.index.Evaluate(criteria.Query, criteria.Fuzzy)
+	} else {
+		for ord, p := range r.products {
+			if p != nil {
+				candidates = append(candidates, ord)
+			}
+		}
+	}
+
+	matched := candidates[:0]
+	for _, ord := range candidates {
+		if criteria.Filters.Matches(*r.products[ord]) {
+			matched = append(matched, ord)
+		}
+	}
+
+	return matched
+}
+
+// groupByModel colapsa los resultados ya ordenados por marca + modelo: el
+// primero de cada grupo lo representa y acumula cantidad de variantes y
+// rango de precios. Los productos sin modelo no se agrupan.
+func groupByModel(hits []model.SearchHit) []model.SearchHit {
+	groups := make(map[string]int) // clave -> posición del representante
+	grouped := hits[:0]
+
+	for _, hit := range hits {
+		key := modelGroupKey(hit.Product)
+		if i, ok := groups[key]; ok {
+			g := grouped[i].Group
+			g.Variants++
+			g.MinPrice = min(g.MinPrice, hit.Product.Price)
+			g.MaxPrice = max(g.MaxPrice, hit.Product.Price)
+			continue
+		}
+
+		groups[key] = len(grouped)
+		hit.Group = &model.HitGroup{
+			Variants: 1,
+			MinPrice: hit.Product.Price,
+			MaxPrice: hit.Product.Price,
+		}
+		grouped = append(grouped, hit)
+	}
+
+	return grouped
+}
+
+func modelGroupKey(p model.Product) string {
+	if strings.TrimSpace(p.Model) == "" {
+		return "id\x00" + p.ID
+	}
+	return analysis.Fold(strings.TrimSpace(p.Brand)) + "\x00" + analysis.Fold(strings.TrimSpace(p.Model))
+}
+
+func completions(p model.Product) map[string]string {
+	return map[string]string{
+		model.SuggestionTitle:    p.Title,
+		model.SuggestionBrand:    p.Brand,
+		model.SuggestionModel:    p.Model,
+		model.SuggestionCategory: p.Category,
+	}
+}
+
+func toDocument(p model.Product) search.Document {
+	return search.Document{
+		ID: p.ID,
+		Fields: map[search.Field]string{
+			search.FieldTitle:       p.Title,
+			search.FieldBrand:       p.Brand,
+			search.FieldModel:       p.Model,
+			search.FieldCategory:    p.Category,
+			search.FieldDescription: p.Description,
+			search.FieldAttributes:  attributeValues(p),
+		},
+	}
+}
+
+// attributeValues une los valores de los atributos ("256 GB", "Negro")
+// para indexarlos como un campo más.
+func attributeValues(p model.Product) string {
+	values := make([]string, len(p.Attributes))
+	for i, attr := range p.Attributes {
+		values[i] = attr.Value
+	}
+	return strings.Join(values, "\n")
+}
+
+// toAttributeFacets ordena los atributos por cuántos resultados los tienen
+// y luego por nombre.
+func toAttributeFacets(attributes map[string]map[string]int) []model.AttributeFacet {
+	facets := make([]model.AttributeFacet, 0, len(attributes))
+	coverage := make(map[string]int, len(attributes))
+	for name, values := range attributes {
+		for _, count := range values {
+			coverage[name] += count
+		}
+		facets = append(facets, model.AttributeFacet{Name: name, Values: toFacetValues(values)})
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		if coverage[facets[i].Name] != coverage[facets[j].Name] {
+			return coverage[facets[i].Name] > coverage[facets[j].Name]
+		}
+		return facets[i].Name < facets[j].Name
+	})
+	return facets
+}
+
+func priceBucket(price float64) int {
+	for i, bound := range priceFacetBounds {
+		if price < bound {
+			return i
+		}
+	}
+	return len(priceFacetBounds)
+}
+
+// toFacetValues ordena por cantidad descendente y luego por valor, para que
+// la respuesta sea determinística.
+func toFacetValues(counts map[string]int) []model.FacetValue {
+	values := make([]model.FacetValue, 0, len(counts))
+	for value, count := range counts {
+		if value == "" {
+			continue
+		}
+		values = append(values, model.FacetValue{Value: value, Count: count})
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	return values
+}
+
+func toPriceRangeFacets(counts []int) []model.PriceRangeFacet {
+	ranges := make([]model.PriceRangeFacet, 0, len(counts))
+	min := 0.0
+
+	for i, count := range counts {
+		var max *float64
+		if i < len(priceFacetBounds) {
+			bound := priceFacetBounds[i]
+			max = &bound
+		}
+
+		if count > 0 {
+			ranges = append(ranges, model.PriceRangeFacet{Min: min, Max: max, Count: count})
+		}
+
+		if max != nil {
+			min = *max
+		}
+	}
 
-	return strings.Contains(title, keyword) ||
-		strings.Contains(desc, keyword) ||
-		strings.Contains(category, keyword) ||
-		strings.Contains(brand, keyword)
+	return ranges
 }
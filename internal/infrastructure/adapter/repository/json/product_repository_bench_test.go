@@ -0,0 +1,214 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/query"
+	"meli-product-api/internal/infrastructure/search"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const benchCatalogSize = 100_000
+
+var (
+	benchOnce     sync.Once
+	benchRepo     *ProductRepository
+	benchProducts []model.Product
+	benchErr      error
+)
+
+var (
+	benchBrands     = []string{"Apple", "Samsung", "Lenovo", "Nike", "Motorola", "Xiaomi", "Sony", "LG", "Adidas", "HP"}
+	benchCategories = []string{"Celulares y Teléfonos", "Computación", "Electrónica, Audio y Video", "Ropa, Bolsas y Calzado", "Hogar y Muebles"}
+	benchNouns      = []string{"Smartphone", "Notebook", "Smart TV", "Zapatillas", "Auriculares", "Tablet", "Monitor", "Parlante", "Reloj", "Mochila"}
+	benchWords      = []string{"pro", "max", "ultra", "lite", "plus", "negro", "blanco", "azul", "gb", "ssd", "4k", "bluetooth", "inalámbrico", "deportivo", "original"}
+	benchRare       = "edicionlimitada"
+)
+
+// syntheticCatalog arma un catálogo reproducible; benchRare aparece en
+// aproximadamente uno de cada mil productos para medir queries selectivas.
+func syntheticCatalog(n int) []model.Product {
+	rng := rand.New(rand.NewSource(42))
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	products := make([]model.Product, n)
+
+	pick := func(words []string) string { return words[rng.Intn(len(words))] }
+
+	for i := range products {
+		brand := pick(benchBrands)
+		noun := pick(benchNouns)
+		title := fmt.Sprintf("%s %s %s %s %d", noun, brand, pick(benchWords), pick(benchWords), rng.Intn(1000))
+		desc := strings.Join([]string{noun, brand, pick(benchWords), pick(benchWords), pick(benchWords), pick(benchWords)}, " ")
+		if i%1000 == 0 {
+			title += " " + benchRare
+		}
+
+		products[i] = model.Product{
+			ID:                fmt.Sprintf("MLA%07d", i),
+			Title:             title,
+			Description:       desc,
+			Price:             float64(rng.Intn(1_000_000)),
+			Condition:         "new",
+			AvailableQuantity: rng.Intn(100),
+			SoldQuantity:      rng.Intn(5000),
+			Category:          pick(benchCategories),
+			Brand:             brand,
+			Model:             fmt.Sprintf("%s-%d", noun, rng.Intn(50)),
+			CreatedAt:         base.Add(time.Duration(i) * time.Minute),
+		}
+	}
+
+	return products
+}
+
+func loadBenchRepo(b *testing.B) *ProductRepository {
+	b.Helper()
+
+	benchOnce.Do(func() {
+		benchProducts = syntheticCatalog(benchCatalogSize)
+
+		data, err := json.Marshal(benchProducts)
+		if err != nil {
+			benchErr = err
+			return
+		}
+
+		path := filepath.Join(os.TempDir(), "meli-bench-products.json")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			benchErr = err
+			return
+		}
+		defer os.Remove(path)
+
+		benchRepo, benchErr = NewProductRepository(path, analysis.NewAnalyzer(), search.NewScorer(nil))
+	})
+
+	if benchErr != nil {
+		b.Fatalf("loading synthetic catalog: %v", benchErr)
+	}
+	return benchRepo
+}
+
+func benchCriteria(keyword string) model.SearchCriteria {
+	node, err := query.Compile(keyword, analysis.NewAnalyzer())
+	if err != nil {
+		panic(err)
+	}
+	return model.SearchCriteria{
+		Keyword: keyword,
+		Query:   node,
+		Sort:    model.SortRelevance,
+	}
+}
+
+func BenchmarkSearchSelective(b *testing.B) {
+	repo := loadBenchRepo(b)
+	ctx := context.Background()
+	criteria := benchCriteria(benchRare)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Search(ctx, criteria, model.Page{Limit: 10}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchBroad(b *testing.B) {
+	repo := loadBenchRepo(b)
+	ctx := context.Background()
+	criteria := benchCriteria("samsung smartphone")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Search(ctx, criteria, model.Page{Limit: 10}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountSelective(b *testing.B) {
+	repo := loadBenchRepo(b)
+	ctx := context.Background()
+	criteria := benchCriteria(benchRare)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Count(ctx, criteria); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchFacetsBroad(b *testing.B) {
+	repo := loadBenchRepo(b)
+	ctx := context.Background()
+	criteria := benchCriteria("samsung")
+	criteria.Facets = true
+	criteria.Histogram.Buckets = 10
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Search(ctx, criteria, model.Page{Limit: 10}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSuggest(b *testing.B) {
+	repo := loadBenchRepo(b)
+	ctx := context.Background()
+	prefixes := []string{"s", "sam", "smart tv", "note", "zapatillas ni"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Suggest(ctx, prefixes[i%len(prefixes)], 5); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSave(b *testing.B) {
+	repo := loadBenchRepo(b)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := benchProducts[i%len(benchProducts)]
+		if err := repo.Save(ctx, p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkLinearScanSelective mide el enfoque anterior (strings.Contains
+// sobre cada producto) como referencia.
+func BenchmarkLinearScanSelective(b *testing.B) {
+	loadBenchRepo(b)
+	keyword := benchRare
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for _, p := range benchProducts {
+			if strings.Contains(strings.ToLower(p.Title), keyword) ||
+				strings.Contains(strings.ToLower(p.Description), keyword) ||
+				strings.Contains(strings.ToLower(p.Category), keyword) ||
+				strings.Contains(strings.ToLower(p.Brand), keyword) {
+				count++
+			}
+		}
+		if count == 0 {
+			b.Fatal("expected matches")
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/query"
+	"meli-product-api/internal/infrastructure/search"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestRepo(t *testing.T, products []model.Product) (*ProductRepository, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "products.json")
+	writeProducts(t, path, products)
+
+	repo, err := NewProductRepository(path, analysis.NewAnalyzer(), search.NewScorer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, path
+}
+
+func writeProducts(t *testing.T, path string, products []model.Product) {
+	t.Helper()
+
+	data, err := json.Marshal(products)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProductRepositoryUpdatesInPlace(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepo(t, []model.Product{
+		{ID: "MLA1", Title: "Notebook Lenovo", Brand: "Lenovo", Price: 100},
+		{ID: "MLA2", Title: "Celular Samsung", Brand: "Samsung", Price: 200},
+	})
+
+	for i := range 100 {
+		p := model.Product{ID: "MLA1", Title: "Notebook Lenovo", Brand: "Lenovo", Price: float64(100 + i)}
+		if err := repo.Save(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Delete(ctx, "MLA2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, model.Product{ID: "MLA3", Title: "Tablet Samsung", Brand: "Samsung"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repo.products) != 2 {
+		t.Errorf("products slots = %d, want 2", len(repo.products))
+	}
+
+	tests := []struct {
+		id        string
+		wantPrice float64
+	}{
+		{id: "MLA1", wantPrice: 199},
+		{id: "MLA3"},
+	}
+	for _, tt := range tests {
+		p, err := repo.FindByID(ctx, tt.id)
+		if err != nil {
+			t.Fatalf("FindByID(%s): %v", tt.id, err)
+		}
+		if p.Price != tt.wantPrice {
+			t.Errorf("FindByID(%s).Price = %v, want %v", tt.id, p.Price, tt.wantPrice)
+		}
+	}
+}
+
+func TestProductRepositoryRemovals(t *testing.T) {
+	catalog := []model.Product{
+		{ID: "MLA1", Title: "Notebook Lenovo", Brand: "Lenovo"},
+		{ID: "MLA2", Title: "Celular Motorola", Brand: "Motorola"},
+	}
+
+	tests := []struct {
+		name   string
+		remove func(t *testing.T, repo *ProductRepository, path string)
+	}{
+		{
+			name: "delete",
+			remove: func(t *testing.T, repo *ProductRepository, path string) {
+				if err := repo.Delete(context.Background(), "MLA2"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "reload without the product",
+			remove: func(t *testing.T, repo *ProductRepository, path string) {
+				writeProducts(t, path, catalog[:1])
+				repo.modTime = repo.modTime.Add(-time.Second)
+				if _, err := repo.Reload(); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, path := newTestRepo(t, catalog)
+
+			var changes []model.ProductChange
+			repo.OnChange(func(c []model.ProductChange) { changes = append(changes, c...) })
+
+			tt.remove(t, repo, path)
+
+			want := []model.ProductChange{{ProductID: "MLA2", Kind: model.ProductRemoved}}
+			if !slices.Equal(changes, want) {
+				t.Errorf("changes = %v, want %v", changes, want)
+			}
+			if _, err := repo.FindByID(ctx, "MLA2"); err == nil {
+				t.Error("FindByID() found the removed product")
+			}
+			if brands, _ := repo.Brands(ctx); !slices.Equal(brands, []string{"Lenovo"}) {
+				t.Errorf("Brands() = %v, want [Lenovo]", brands)
+			}
+			if suggestions, _ := repo.Suggest(ctx, "moto", 5); len(suggestions) != 0 {
+				t.Errorf("Suggest(moto) = %v, want none", suggestions)
+			}
+			if corrections, _ := repo.Correct(ctx, []string{"motorla"}); !slices.Equal(corrections[0], []string{"motorla"}) {
+				t.Errorf("Correct(motorla) = %v, want no correction", corrections[0])
+			}
+		})
+	}
+}
+
+func TestProductRepositoryCursorGeneration(t *testing.T) {
+	tests := []struct {
+		name        string
+		sort        model.SortOrder
+		change      bool
+		wantExpired bool
+	}{
+		{name: "relevance, same catalog", sort: model.SortRelevance},
+		{name: "relevance, catalog changed", sort: model.SortRelevance, change: true, wantExpired: true},
+		{name: "price, catalog changed", sort: model.SortPriceAsc, change: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, _ := newTestRepo(t, []model.Product{
+				{ID: "MLA1", Title: "Notebook Lenovo", Price: 300},
+				{ID: "MLA2", Title: "Notebook Samsung", Price: 200},
+				{ID: "MLA3", Title: "Notebook HP", Price: 100},
+			})
+
+			node, err := query.Compile("notebook", repo.analyzer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			criteria := model.SearchCriteria{Keyword: "notebook", Query: node, Sort: tt.sort}
+
+			first, err := repo.Search(ctx, criteria, model.Page{Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cursor := tt.sort.CursorAt(first.Hits[0])
+
+			if tt.change {
+				if err := repo.Save(ctx, model.Product{ID: "MLA4", Title: "Notebook Asus", Price: 400}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			next, err := repo.Search(ctx, criteria, model.Page{Limit: 10, After: &cursor})
+			if tt.wantExpired {
+				if !errors.Is(err, model.ErrCursorExpired) {
+					t.Fatalf("Search() err = %v, want ErrCursorExpired", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, hit := range next.Hits {
+				if hit.Product.ID == first.Hits[0].Product.ID {
+					t.Errorf("next page repeats %s", hit.Product.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestProductRepositorySearchTotals(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestRepo(t, []model.Product{
+		{ID: "MLA1", Title: "Celular Samsung A15 128gb", Brand: "Samsung", Model: "A15", Category: "Celulares", Price: 100},
+		{ID: "MLA2", Title: "Celular Samsung A15 256gb", Brand: "Samsung", Model: "A15", Category: "Celulares", Price: 150},
+		{ID: "MLA3", Title: "Celular Motorola G54", Brand: "Motorola", Model: "G54", Category: "Celulares", Price: 120},
+		{ID: "MLA4", Title: "Notebook Lenovo", Brand: "Lenovo", Category: "Computación", Price: 500},
+	})
+
+	node, err := query.Compile("celular", repo.analyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		criteria   model.SearchCriteria
+		wantHits   int
+		wantTotal  int
+		wantGroups int
+		wantFacets bool
+	}{
+		{
+			name:      "page only",
+			criteria:  model.SearchCriteria{Query: node, Sort: model.SortPriceAsc},
+			wantHits:  2,
+			wantTotal: 3,
+		},
+		{
+			name:       "with facets",
+			criteria:   model.SearchCriteria{Query: node, Sort: model.SortPriceAsc, Facets: true, Histogram: model.HistogramSpec{Buckets: 2}},
+			wantHits:   2,
+			wantTotal:  3,
+			wantFacets: true,
+		},
+		{
+			name:       "grouped by model",
+			criteria:   model.SearchCriteria{Query: node, Sort: model.SortPriceAsc, GroupBy: model.GroupByModel, Facets: true, Histogram: model.HistogramSpec{Buckets: 2}},
+			wantHits:   2,
+			wantTotal:  3,
+			wantGroups: 2,
+			wantFacets: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.Search(ctx, tt.criteria, model.Page{Limit: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(found.Hits) != tt.wantHits || found.Total != tt.wantTotal || found.Groups != tt.wantGroups {
+				t.Errorf("Search() = %d hits, total %d, groups %d; want %d, %d, %d",
+					len(found.Hits), found.Total, found.Groups, tt.wantHits, tt.wantTotal, tt.wantGroups)
+			}
+
+			if !tt.wantFacets {
+				if found.Facets.Categories != nil || found.PriceHistogram != nil {
+					t.Errorf("Search() computed facets without being asked")
+				}
+				return
+			}
+			// Facets and histogram count listings even when hits are grouped
+			if got := sumFacet(found.Facets.Categories); got != tt.wantTotal {
+				t.Errorf("category facets count %d listings, want %d", got, tt.wantTotal)
+			}
+			if got := sumHistogram(found.PriceHistogram); got != tt.wantTotal {
+				t.Errorf("price histogram counts %d listings, want %d", got, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProductRepositorySearchIsConsistentWhileChanging(t *testing.T) {
+	ctx := context.Background()
+	var products []model.Product
+	for i := range 200 {
+		products = append(products, model.Product{
+			ID:       fmt.Sprintf("MLA%d", i),
+			Title:    "Notebook Lenovo",
+			Category: fmt.Sprintf("cat%d", i%3),
+			Price:    float64(100 + i),
+		})
+	}
+	repo, _ := newTestRepo(t, products)
+
+	node, err := query.Compile("notebook", repo.analyzer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	criteria := model.SearchCriteria{Query: node, Sort: model.SortRelevance, Facets: true, Histogram: model.HistogramSpec{Buckets: 5}}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, p := range products {
+			if err := repo.Delete(ctx, p.ID); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for {
+		found, err := repo.Search(ctx, criteria, model.Page{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sumFacet(found.Facets.Categories); got != found.Total {
+			t.Fatalf("category facets count %d listings, total is %d", got, found.Total)
+		}
+		if got := sumHistogram(found.PriceHistogram); got != found.Total {
+			t.Fatalf("price histogram counts %d listings, total is %d", got, found.Total)
+		}
+		if len(found.Hits) != min(10, found.Total) {
+			t.Fatalf("page has %d hits, total is %d", len(found.Hits), found.Total)
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
+
+func sumFacet(values []model.FacetValue) int {
+	sum := 0
+	for _, v := range values {
+		sum += v.Count
+	}
+	return sum
+}
+
+func sumHistogram(histogram *model.PriceHistogram) int {
+	if histogram == nil {
+		return 0
+	}
+	sum := 0
+	for _, b := range histogram.Buckets {
+		sum += b.Count
+	}
+	return sum
+}
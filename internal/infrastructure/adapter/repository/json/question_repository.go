@@ -39,7 +39,9 @@ func (r *QuestionRepository) load() error {
 
 func (r *QuestionRepository) GetByProductID(ctx context.Context, productID string, limit int) ([]model.Question, error) {
 	// Simulate network latency
-	time.Sleep(18 * time.Millisecond)
+	if err := simulateLatency(ctx, 18*time.Millisecond); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
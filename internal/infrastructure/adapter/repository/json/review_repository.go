@@ -39,7 +39,9 @@ func (r *ReviewRepository) load() error {
 
 func (r *ReviewRepository) GetByProductID(ctx context.Context, productID string) ([]model.Review, error) {
 	// Simulate network latency
-	time.Sleep(20 * time.Millisecond)
+	if err := simulateLatency(ctx, 20*time.Millisecond); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -0,0 +1,252 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+const (
+	// maxNotificationsPerUser acota cuántas notificaciones se guardan por
+	// usuario; al superarlo se descartan las más viejas.
+	maxNotificationsPerUser = 200
+	// maxNotifiedProducts acota los productos notificados que recuerda cada
+	// búsqueda; al superarlo se olvidan los más viejos, que podrían volver
+	// a notificarse si cambian. Los que ya cumplían la búsqueda al
+	// guardarla no se acotan.
+	maxNotifiedProducts = 1000
+)
+
+// savedSearchFile es el formato del archivo de estado.
+type savedSearchFile struct {
+	Searches      []*model.SavedSearch `json:"searches"`
+	Notifications []model.Notification `json:"notifications"`
+}
+
+// SavedSearchRepository guarda búsquedas y notificaciones en memoria y las
+// persiste en un archivo JSON después de cada cambio. Implementa
+// port.SavedSearchRepository y port.NotificationRepository.
+type SavedSearchRepository struct {
+	mu            sync.RWMutex
+	searches      []*model.SavedSearch
+	notifications []model.Notification // más viejas primero
+	filePath      string
+}
+
+func NewSavedSearchRepository(filePath string) (*SavedSearchRepository, error) {
+	repo := &SavedSearchRepository{
+		filePath: filePath,
+	}
+
+	if err := repo.load(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SavedSearchRepository) load() error {
+	data, err := os.ReadFile(r.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file savedSearchFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	r.searches = file.Searches
+	r.notifications = file.Notifications
+	return nil
+}
+
+// save escribe el nuevo estado y, solo si lo pudo escribir, lo adopta en
+// memoria: una falla de disco no deja cambios que se perderían al
+// reiniciar. Los llamadores arman searches y notifications sin modificar
+// los actuales. Debe llamarse con el lock de escritura tomado. Escribe a
+// un archivo temporal y lo renombra, así un corte no deja el archivo a
+// medias.
+func (r *SavedSearchRepository) save(searches []*model.SavedSearch, notifications []model.Notification) error {
+	data, err := json.MarshalIndent(savedSearchFile{
+		Searches:      searches,
+		Notifications: notifications,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0o755); err != nil {
+		return err
+	}
+	tmp := r.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, r.filePath); err != nil {
+		return err
+	}
+
+	r.searches = searches
+	r.notifications = notifications
+	return nil
+}
+
+func (r *SavedSearchRepository) Create(ctx context.Context, search model.SavedSearch, maxPerUser int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, s := range r.searches {
+		if s.UserID == search.UserID {
+			count++
+		}
+	}
+	if count >= maxPerUser {
+		return port.ErrSavedSearchLimit
+	}
+
+	return r.save(append(slices.Clip(r.searches), &search), r.notifications)
+}
+
+func (r *SavedSearchRepository) FindByUser(ctx context.Context, userID string) ([]model.SavedSearch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var searches []model.SavedSearch
+	for _, s := range r.searches {
+		if s.UserID == userID {
+			searches = append(searches, copySavedSearch(s))
+		}
+	}
+
+	return searches, nil
+}
+
+func (r *SavedSearchRepository) FindAll(ctx context.Context) ([]model.SavedSearch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	searches := make([]model.SavedSearch, len(r.searches))
+	for i, s := range r.searches {
+		searches[i] = copySavedSearch(s)
+	}
+
+	return searches, nil
+}
+
+// Delete borra la búsqueda y sus notificaciones.
+func (r *SavedSearchRepository) Delete(ctx context.Context, userID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.searches, func(s *model.SavedSearch) bool {
+		return s.ID == id && s.UserID == userID
+	})
+	if i < 0 {
+		return port.ErrSavedSearchNotFound
+	}
+
+	searches := slices.Delete(slices.Clone(r.searches), i, i+1)
+	notifications := slices.DeleteFunc(slices.Clone(r.notifications), func(n model.Notification) bool {
+		return n.SavedSearchID == id
+	})
+	return r.save(searches, notifications)
+}
+
+func (r *SavedSearchRepository) Notify(ctx context.Context, notifications []model.Notification) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Updated searches are copies, so a failed save leaves r untouched
+	searches := slices.Clone(r.searches)
+	byID := make(map[string]int, len(searches))
+	for i, s := range searches {
+		byID[s.ID] = i
+	}
+	updated := make(map[int]bool)
+
+	var added []model.Notification
+	for _, n := range notifications {
+		// The search may have been deleted while it was being evaluated
+		i, ok := byID[n.SavedSearchID]
+		if !ok || searches[i].Knows(n.ProductID) {
+			continue
+		}
+		if !updated[i] {
+			c := copySavedSearch(searches[i])
+			searches[i] = &c
+			updated[i] = true
+		}
+		searches[i].NotifiedProducts = append(searches[i].NotifiedProducts, n.ProductID)
+		added = append(added, n)
+	}
+	if len(added) == 0 {
+		return 0, nil
+	}
+
+	for i := range updated {
+		searches[i].NotifiedProducts = capNotifiedProducts(searches[i].NotifiedProducts)
+	}
+	if err := r.save(searches, trimNotifications(slices.Concat(r.notifications, added))); err != nil {
+		return 0, err
+	}
+	return len(added), nil
+}
+
+// trimNotifications descarta las notificaciones más viejas de cada usuario
+// que supere maxNotificationsPerUser.
+func trimNotifications(notifications []model.Notification) []model.Notification {
+	perUser := make(map[string]int)
+	for _, n := range notifications {
+		perUser[n.UserID]++
+	}
+
+	kept := notifications[:0]
+	for _, n := range notifications {
+		if perUser[n.UserID] > maxNotificationsPerUser {
+			perUser[n.UserID]--
+			continue
+		}
+		kept = append(kept, n)
+	}
+	return kept
+}
+
+// capNotifiedProducts conserva los últimos maxNotifiedProducts productos.
+func capNotifiedProducts(ids []string) []string {
+	if len(ids) <= maxNotifiedProducts {
+		return ids
+	}
+	return slices.Clone(ids[len(ids)-maxNotifiedProducts:])
+}
+
+func (r *SavedSearchRepository) FindNotifications(ctx context.Context, userID string, limit int) ([]model.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := []model.Notification{}
+	for i := len(r.notifications) - 1; i >= 0 && len(notifications) < limit; i-- {
+		if r.notifications[i].UserID == userID {
+			notifications = append(notifications, r.notifications[i])
+		}
+	}
+
+	return notifications, nil
+}
+
+func copySavedSearch(s *model.SavedSearch) model.SavedSearch {
+	c := *s
+	c.KnownProducts = slices.Clone(s.KnownProducts)
+	c.NotifiedProducts = slices.Clone(s.NotifiedProducts)
+	return c
+}
@@ -0,0 +1,200 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func newTestSavedSearchRepo(t *testing.T) *SavedSearchRepository {
+	t.Helper()
+
+	repo, err := NewSavedSearchRepository(filepath.Join(t.TempDir(), "saved_searches.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+// breakDisk hace que cualquier escritura posterior del repositorio falle.
+func breakDisk(t *testing.T, repo *SavedSearchRepository) {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo.filePath = filepath.Join(file, "saved_searches.json")
+}
+
+func TestSavedSearchRepositoryCreateLimitIsAtomic(t *testing.T) {
+	const limit = 5
+	ctx := context.Background()
+	repo := newTestSavedSearchRepo(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.Create(ctx, model.SavedSearch{ID: fmt.Sprint(i), UserID: "u1"}, limit)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	rejected := 0
+	for err := range errs {
+		if errors.Is(err, port.ErrSavedSearchLimit) {
+			rejected++
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	searches, _ := repo.FindByUser(ctx, "u1")
+	if len(searches) != limit || rejected != 20-limit {
+		t.Errorf("saved %d and rejected %d, want %d and %d", len(searches), rejected, limit, 20-limit)
+	}
+	if err := repo.Create(ctx, model.SavedSearch{ID: "other", UserID: "u2"}, limit); err != nil {
+		t.Errorf("Create() for another user err = %v", err)
+	}
+}
+
+func TestSavedSearchRepositoryFailedSaveKeepsState(t *testing.T) {
+	ctx := context.Background()
+	notification := model.Notification{ID: "n1", UserID: "u1", SavedSearchID: "s1", ProductID: "MLA2"}
+
+	tests := []struct {
+		name   string
+		change func(repo *SavedSearchRepository) error
+	}{
+		{
+			name: "create",
+			change: func(repo *SavedSearchRepository) error {
+				return repo.Create(ctx, model.SavedSearch{ID: "s2", UserID: "u1"}, 10)
+			},
+		},
+		{
+			name:   "delete",
+			change: func(repo *SavedSearchRepository) error { return repo.Delete(ctx, "u1", "s1") },
+		},
+		{
+			name: "notify",
+			change: func(repo *SavedSearchRepository) error {
+				_, err := repo.Notify(ctx, []model.Notification{notification})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSavedSearchRepo(t)
+			search := model.SavedSearch{ID: "s1", UserID: "u1", KnownProducts: []string{"MLA1"}}
+			if err := repo.Create(ctx, search, 10); err != nil {
+				t.Fatal(err)
+			}
+			breakDisk(t, repo)
+
+			if err := tt.change(repo); err == nil {
+				t.Fatal("want the write to fail")
+			}
+
+			searches, _ := repo.FindAll(ctx)
+			if len(searches) != 1 || searches[0].ID != "s1" || len(searches[0].KnownProducts) != 1 {
+				t.Errorf("searches = %+v, want only s1 unchanged", searches)
+			}
+			if notifications, _ := repo.FindNotifications(ctx, "u1", 10); len(notifications) != 0 {
+				t.Errorf("notifications = %+v, want none", notifications)
+			}
+		})
+	}
+}
+
+func TestSavedSearchRepositoryNotify(t *testing.T) {
+	ctx := context.Background()
+	notify := func(productID string) model.Notification {
+		return model.Notification{ID: "n-" + productID, UserID: "u1", SavedSearchID: "s1", ProductID: productID}
+	}
+
+	// More initial matches than the notified products a search remembers
+	known := make([]string, maxNotifiedProducts+10)
+	for i := range known {
+		known[i] = fmt.Sprintf("MLA%d", i)
+	}
+
+	tests := []struct {
+		name          string
+		notifications []model.Notification
+		wantAdded     int
+	}{
+		{name: "new product", notifications: []model.Notification{notify("new")}, wantAdded: 1},
+		{name: "same product twice", notifications: []model.Notification{notify("new"), notify("new")}, wantAdded: 1},
+		{name: "oldest initial match", notifications: []model.Notification{notify(known[0])}},
+		{name: "newest initial match", notifications: []model.Notification{notify(known[len(known)-1])}},
+		{
+			name:          "deleted search",
+			notifications: []model.Notification{{ID: "gone", UserID: "u1", SavedSearchID: "deleted", ProductID: "new"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSavedSearchRepo(t)
+			if err := repo.Create(ctx, model.SavedSearch{ID: "s1", UserID: "u1", KnownProducts: known}, 10); err != nil {
+				t.Fatal(err)
+			}
+
+			added, err := repo.Notify(ctx, tt.notifications)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("Notify() = %d, want %d", added, tt.wantAdded)
+			}
+
+			// Reloading from disk sees the same state
+			reloaded, err := NewSavedSearchRepository(repo.filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			searches, _ := reloaded.FindAll(ctx)
+			if len(searches[0].KnownProducts) != len(known) {
+				t.Errorf("known products = %d, want all %d initial matches", len(searches[0].KnownProducts), len(known))
+			}
+			if notifications, _ := reloaded.FindNotifications(ctx, "u1", 10); len(notifications) != tt.wantAdded {
+				t.Errorf("notifications = %+v, want %d", notifications, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestSavedSearchRepositoryCapsNotifiedProducts(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSavedSearchRepo(t)
+	if err := repo.Create(ctx, model.SavedSearch{ID: "s1", UserID: "u1"}, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := make([]model.Notification, maxNotifiedProducts+10)
+	for i := range notifications {
+		notifications[i] = model.Notification{ID: fmt.Sprint(i), UserID: "u1", SavedSearchID: "s1", ProductID: fmt.Sprintf("MLA%d", i)}
+	}
+	if _, err := repo.Notify(ctx, notifications); err != nil {
+		t.Fatal(err)
+	}
+
+	searches, _ := repo.FindAll(ctx)
+	notified := searches[0].NotifiedProducts
+	if len(notified) != maxNotifiedProducts || notified[len(notified)-1] != fmt.Sprintf("MLA%d", maxNotifiedProducts+9) {
+		t.Errorf("notified products = %d, want the last %d", len(notified), maxNotifiedProducts)
+	}
+}
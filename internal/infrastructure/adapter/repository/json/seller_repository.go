@@ -3,8 +3,8 @@ package json
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
 	"os"
 	"sync"
 	"time"
@@ -49,14 +49,16 @@ func (r *SellerRepository) load() error {
 
 func (r *SellerRepository) GetByID(ctx context.Context, sellerID string) (*model.Seller, error) {
 	// Simulate network latency
-	time.Sleep(15 * time.Millisecond)
+	if err := simulateLatency(ctx, 15*time.Millisecond); err != nil {
+		return nil, err
+	}
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	seller, exists := r.sellers[sellerID]
 	if !exists {
-		return nil, errors.New("seller not found")
+		return nil, port.ErrSellerNotFound
 	}
 
 	return &seller, nil
@@ -0,0 +1,99 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"meli-product-api/internal/domain/analysis"
+	"meli-product-api/internal/domain/query"
+	"os"
+	"sync"
+	"time"
+)
+
+// synonymFile es el formato del archivo de sinónimos:
+//
+//	{
+//	  "equivalent": [["celular", "smartphone"]],
+//	  "one_way": {"celular": ["iphone"]}
+//	}
+type synonymFile struct {
+	Equivalent [][]string          `json:"equivalent"`
+	OneWay     map[string][]string `json:"one_way"`
+}
+
+type SynonymRepository struct {
+	mu       sync.RWMutex
+	synonyms *query.Synonyms
+	modTime  time.Time
+	analyzer *analysis.Analyzer
+	filePath string
+}
+
+func NewSynonymRepository(filePath string, analyzer *analysis.Analyzer) (*SynonymRepository, error) {
+	repo := &SynonymRepository{
+		filePath: filePath,
+		analyzer: analyzer,
+	}
+
+	if _, err := repo.Reload(); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *SynonymRepository) Synonyms(ctx context.Context) (*query.Synonyms, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.synonyms, nil
+}
+
+// Reload vuelve a leer el archivo si cambió desde la última carga y
+// reporta si hubo cambios. Si el archivo nuevo es inválido se conservan
+// los sinónimos anteriores.
+func (r *SynonymRepository) Reload() (bool, error) {
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := r.synonyms != nil && info.ModTime().Equal(r.modTime)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return false, err
+	}
+
+	var file synonymFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		// Remember the broken version so it is reported once, not on every check
+		r.mu.Lock()
+		r.modTime = info.ModTime()
+		r.mu.Unlock()
+		return false, fmt.Errorf("parsing synonyms file %s: %w", r.filePath, err)
+	}
+
+	synonyms := query.NewSynonyms(r.analyzer, file.Equivalent, file.OneWay)
+
+	r.mu.Lock()
+	r.synonyms = synonyms
+	r.modTime = info.ModTime()
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// Len devuelve la cantidad de claves cargadas.
+func (r *SynonymRepository) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.synonyms.Len()
+}
@@ -0,0 +1,340 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"meli-product-api/internal/domain/model"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BucketSize es la resolución de las ventanas: las búsquedas se acumulan
+// en intervalos de este largo y una ventana suma los intervalos que toca.
+const BucketSize = 5 * time.Minute
+
+// slowestPerBucket acota cuántas búsquedas lentas se guardan por intervalo.
+const slowestPerBucket = 50
+
+// Store es un almacén de analítica en memoria con ventanas móviles. Los
+// intervalos más viejos que la retención se descartan. Con filePath se
+// persiste a disco con Save y se recupera al crearlo.
+type Store struct {
+	mu sync.Mutex
+	// saveMu serializa Save para que dos escrituras no pisen el temporal
+	saveMu    sync.Mutex
+	buckets   map[int64]*bucket
+	retention time.Duration
+	filePath  string
+	dirty     bool
+	now       func() time.Time
+}
+
+type bucket struct {
+	Start   time.Time             `json:"start"`
+	Stats   map[string]*queryStat `json:"stats"`
+	Slowest []model.SearchEvent   `json:"slowest"`
+}
+
+// queryStat acumula las búsquedas de una combinación query + filtros.
+type queryStat struct {
+	Query        string        `json:"query"`
+	Filters      string        `json:"filters,omitempty"`
+	Searches     int           `json:"searches"`
+	ZeroResults  int           `json:"zero_results"`
+	TotalLatency time.Duration `json:"total_latency"`
+	LastAt       time.Time     `json:"last_at"`
+}
+
+type snapshot struct {
+	Buckets []*bucket `json:"buckets"`
+}
+
+// NewStore crea el almacén y, si filePath existe, carga lo guardado. Un
+// filePath vacío deja la analítica solo en memoria.
+func NewStore(filePath string, retention time.Duration) (*Store, error) {
+	s := &Store{
+		buckets:   make(map[int64]*bucket),
+		retention: retention,
+		filePath:  filePath,
+		now:       time.Now,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) load() error {
+	if s.filePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+
+	for _, b := range snap.Buckets {
+		if b.Stats == nil {
+			b.Stats = make(map[string]*queryStat)
+		}
+		s.buckets[bucketKey(b.Start)] = b
+	}
+	s.prune()
+
+	return nil
+}
+
+// Save escribe el contenido actual si cambió desde el último Save. Si la
+// escritura falla los cambios siguen pendientes para el próximo Save.
+func (s *Store) Save() error {
+	if s.filePath == "" {
+		return nil
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	s.prune()
+	snap := snapshot{Buckets: make([]*bucket, 0, len(s.buckets))}
+	for _, b := range s.buckets {
+		snap.Buckets = append(snap.Buckets, b)
+	}
+	sort.Slice(snap.Buckets, func(i, j int) bool { return snap.Buckets[i].Start.Before(snap.Buckets[j].Start) })
+	data, err := json.Marshal(snap)
+	// Records made while writing mark the store dirty again
+	s.dirty = false
+	s.mu.Unlock()
+
+	if err == nil {
+		err = s.write(data)
+	}
+	if err != nil {
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// write escribe a un archivo temporal y lo renombra, así un corte no deja
+// el archivo a medias.
+func (s *Store) write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0o755); err != nil {
+		return err
+	}
+	tmp := s.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.filePath)
+}
+
+func (s *Store) Record(ctx context.Context, event model.SearchEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := bucketKey(event.At)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{
+			Start: time.Unix(key*int64(BucketSize/time.Second), 0).UTC(),
+			Stats: make(map[string]*queryStat),
+		}
+		s.buckets[key] = b
+		s.prune()
+	}
+
+	statKey := event.Query + "\x00" + event.Filters
+	stat, ok := b.Stats[statKey]
+	if !ok {
+		stat = &queryStat{Query: event.Query, Filters: event.Filters}
+		b.Stats[statKey] = stat
+	}
+	stat.Searches++
+	if event.Results == 0 {
+		stat.ZeroResults++
+	}
+	stat.TotalLatency += event.Latency
+	if event.At.After(stat.LastAt) {
+		stat.LastAt = event.At
+	}
+
+	b.Slowest = insertSlowest(b.Slowest, event, slowestPerBucket)
+	s.dirty = true
+
+	return nil
+}
+
+func (s *Store) TopQueries(ctx context.Context, window time.Duration, limit int) ([]model.QueryStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := make(map[string]*model.QueryStats)
+	latency := make(map[string]time.Duration)
+	for _, b := range s.window(window) {
+		for _, stat := range b.Stats {
+			// Browse searches have no query to rank
+			if stat.Query == "" {
+				continue
+			}
+			m := merge(merged, stat.Query, stat)
+			m.Filters = ""
+			latency[stat.Query] += stat.TotalLatency
+		}
+	}
+
+	return ranked(merged, latency, limit, func(a, b *model.QueryStats) bool {
+		return a.Searches > b.Searches
+	}), nil
+}
+
+func (s *Store) ZeroResultQueries(ctx context.Context, window time.Duration, limit int) ([]model.QueryStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := make(map[string]*model.QueryStats)
+	latency := make(map[string]time.Duration)
+	for _, b := range s.window(window) {
+		for key, stat := range b.Stats {
+			if stat.ZeroResults == 0 {
+				continue
+			}
+			merge(merged, key, stat)
+			latency[key] += stat.TotalLatency
+		}
+	}
+
+	return ranked(merged, latency, limit, func(a, b *model.QueryStats) bool {
+		return a.ZeroResults > b.ZeroResults
+	}), nil
+}
+
+func (s *Store) SlowestSearches(ctx context.Context, window time.Duration, limit int) ([]model.SearchEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Buckets only tell whether an event may be in the window; the event
+	// timestamp decides
+	since := s.now().Add(-window)
+
+	var slowest []model.SearchEvent
+	for _, b := range s.window(window) {
+		for _, event := range b.Slowest {
+			if !event.At.Before(since) {
+				slowest = insertSlowest(slowest, event, limit)
+			}
+		}
+	}
+
+	return slowest, nil
+}
+
+// window devuelve los intervalos que se solapan con los últimos d.
+func (s *Store) window(d time.Duration) []*bucket {
+	since := s.now().Add(-d)
+
+	var out []*bucket
+	for _, b := range s.buckets {
+		if b.Start.Add(BucketSize).After(since) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func (s *Store) prune() {
+	oldest := s.now().Add(-s.retention)
+	for key, b := range s.buckets {
+		if !b.Start.Add(BucketSize).After(oldest) {
+			delete(s.buckets, key)
+			s.dirty = true
+		}
+	}
+}
+
+func bucketKey(t time.Time) int64 {
+	return t.Unix() / int64(BucketSize/time.Second)
+}
+
+func merge(merged map[string]*model.QueryStats, key string, stat *queryStat) *model.QueryStats {
+	m, ok := merged[key]
+	if !ok {
+		m = &model.QueryStats{Query: stat.Query, Filters: stat.Filters}
+		merged[key] = m
+	}
+	m.Searches += stat.Searches
+	m.ZeroResults += stat.ZeroResults
+	if stat.LastAt.After(m.LastSearchedAt) {
+		m.LastSearchedAt = stat.LastAt
+	}
+	return m
+}
+
+// ranked ordena por better, desempata por la búsqueda más reciente y luego
+// por query, y completa la latencia promedio.
+func ranked(merged map[string]*model.QueryStats, latency map[string]time.Duration, limit int, better func(a, b *model.QueryStats) bool) []model.QueryStats {
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := merged[keys[i]], merged[keys[j]]
+		if better(a, b) != better(b, a) {
+			return better(a, b)
+		}
+		if !a.LastSearchedAt.Equal(b.LastSearchedAt) {
+			return a.LastSearchedAt.After(b.LastSearchedAt)
+		}
+		return keys[i] < keys[j]
+	})
+
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	out := make([]model.QueryStats, len(keys))
+	for i, key := range keys {
+		out[i] = *merged[key]
+		out[i].AvgLatency = latency[key] / time.Duration(out[i].Searches)
+	}
+	return out
+}
+
+// insertSlowest agrega event manteniendo la lista ordenada por latencia
+// descendente y con a lo sumo limit elementos.
+func insertSlowest(events []model.SearchEvent, event model.SearchEvent, limit int) []model.SearchEvent {
+	i := sort.Search(len(events), func(i int) bool { return events[i].Latency < event.Latency })
+	if i >= limit {
+		return events
+	}
+
+	events = append(events, model.SearchEvent{})
+	copy(events[i+1:], events[i:])
+	events[i] = event
+
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
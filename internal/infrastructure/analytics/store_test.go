@@ -0,0 +1,291 @@
+package analytics
+
+import (
+	"context"
+	"meli-product-api/internal/domain/model"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testStart cae dos minutos dentro de un intervalo, así los eventos de un
+// mismo test comparten intervalo salvo que se corran a propósito.
+var testStart = time.Now().UTC().Truncate(time.Hour).Add(2 * time.Minute)
+
+// fakeClock es un reloj que solo avanza cuando el test lo pide.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore(t *testing.T, filePath string, retention time.Duration) (*Store, *fakeClock) {
+	t.Helper()
+
+	s, err := NewStore(filePath, retention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &fakeClock{t: testStart}
+	s.now = clock.now
+	return s, clock
+}
+
+func record(t *testing.T, s *Store, events ...model.SearchEvent) {
+	t.Helper()
+
+	for _, event := range events {
+		if err := s.Record(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func queries(stats []model.QueryStats) []string {
+	out := make([]string, len(stats))
+	for i, stat := range stats {
+		out[i] = stat.Query
+	}
+	return out
+}
+
+func TestStoreWindow(t *testing.T) {
+	s, _ := newTestStore(t, "", 24*time.Hour)
+	record(t, s,
+		model.SearchEvent{Query: "now", At: testStart},
+		model.SearchEvent{Query: "ten minutes ago", At: testStart.Add(-10 * time.Minute)},
+		model.SearchEvent{Query: "two hours ago", At: testStart.Add(-2 * time.Hour)},
+	)
+
+	tests := []struct {
+		window time.Duration
+		want   []string
+	}{
+		// Only the current bucket overlaps the last minute
+		{window: time.Minute, want: []string{"now"}},
+		{window: 5 * time.Minute, want: []string{"now"}},
+		// The previous bucket ends after the window starts
+		{window: 15 * time.Minute, want: []string{"now", "ten minutes ago"}},
+		{window: 3 * time.Hour, want: []string{"now", "ten minutes ago", "two hours ago"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.window.String(), func(t *testing.T) {
+			stats, err := s.TopQueries(context.Background(), tt.window, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := queries(stats); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopQueries(%v) = %q, want %q", tt.window, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    []string
+	}{
+		{name: "within retention", advance: 30 * time.Minute, want: []string{"new", "old"}},
+		{name: "past retention", advance: 2 * time.Hour, want: []string{"new"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, clock := newTestStore(t, "", time.Hour)
+			record(t, s, model.SearchEvent{Query: "old", At: clock.now()})
+			clock.advance(tt.advance)
+			record(t, s, model.SearchEvent{Query: "new", At: clock.now()})
+
+			stats, err := s.TopQueries(context.Background(), 24*time.Hour, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := queries(stats); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopQueries() = %q, want %q", got, tt.want)
+			}
+			if len(s.buckets) != len(tt.want) {
+				t.Errorf("kept %d buckets, want %d", len(s.buckets), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestStoreTopQueries(t *testing.T) {
+	s, _ := newTestStore(t, "", 24*time.Hour)
+	record(t, s,
+		model.SearchEvent{Query: "iphone", Results: 3, Latency: 10 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "iphone", Results: 3, Latency: 20 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "iphone", Filters: "condition=new", Results: 0, Latency: 30 * time.Millisecond, At: testStart.Add(time.Second)},
+		model.SearchEvent{Query: "samsung", Results: 1, Latency: 5 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "motorola", Results: 1, Latency: 5 * time.Millisecond, At: testStart.Add(time.Second)},
+		// Browse searches outnumber every query but have nothing to rank
+		model.SearchEvent{Filters: "category=celulares", Results: 5, At: testStart},
+		model.SearchEvent{Filters: "category=celulares", Results: 5, At: testStart},
+		model.SearchEvent{Filters: "category=celulares", Results: 5, At: testStart},
+		model.SearchEvent{Filters: "category=celulares", Results: 5, At: testStart},
+	)
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []model.QueryStats
+	}{
+		{
+			name:  "filters merged and ties broken by recency",
+			limit: 10,
+			want: []model.QueryStats{
+				{Query: "iphone", Searches: 3, ZeroResults: 1, AvgLatency: 20 * time.Millisecond, LastSearchedAt: testStart.Add(time.Second)},
+				{Query: "motorola", Searches: 1, AvgLatency: 5 * time.Millisecond, LastSearchedAt: testStart.Add(time.Second)},
+				{Query: "samsung", Searches: 1, AvgLatency: 5 * time.Millisecond, LastSearchedAt: testStart},
+			},
+		},
+		{
+			name:  "limit",
+			limit: 1,
+			want: []model.QueryStats{
+				{Query: "iphone", Searches: 3, ZeroResults: 1, AvgLatency: 20 * time.Millisecond, LastSearchedAt: testStart.Add(time.Second)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.TopQueries(context.Background(), time.Hour, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopQueries() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreZeroResultQueries(t *testing.T) {
+	s, _ := newTestStore(t, "", 24*time.Hour)
+	record(t, s,
+		model.SearchEvent{Query: "zapatilas runing", Filters: "condition=new", Latency: 4 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "zapatilas runing", Filters: "condition=new", Latency: 2 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "zapatilas runing", Latency: time.Millisecond, At: testStart},
+		model.SearchEvent{Filters: "category=autos", Latency: time.Millisecond, At: testStart.Add(time.Second)},
+		model.SearchEvent{Query: "iphone", Results: 3, At: testStart.Add(time.Minute)},
+	)
+
+	got, err := s.ZeroResultQueries(context.Background(), time.Hour, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []model.QueryStats{
+		{Query: "zapatilas runing", Filters: "condition=new", Searches: 2, ZeroResults: 2, AvgLatency: 3 * time.Millisecond, LastSearchedAt: testStart},
+		// A browse without results points at the filters, so it is kept
+		{Filters: "category=autos", Searches: 1, ZeroResults: 1, AvgLatency: time.Millisecond, LastSearchedAt: testStart.Add(time.Second)},
+		{Query: "zapatilas runing", Searches: 1, ZeroResults: 1, AvgLatency: time.Millisecond, LastSearchedAt: testStart},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ZeroResultQueries() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStoreSlowestSearches(t *testing.T) {
+	s, _ := newTestStore(t, "", 24*time.Hour)
+	record(t, s,
+		model.SearchEvent{Query: "fast", Latency: time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "slow", Latency: 50 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "slower", Latency: 80 * time.Millisecond, At: testStart.Add(-90 * time.Second)},
+		model.SearchEvent{Query: "old", Latency: time.Second, At: testStart.Add(-20 * time.Minute)},
+	)
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		limit  int
+		want   []string
+	}{
+		{name: "by latency", window: time.Hour, limit: 10, want: []string{"old", "slower", "slow", "fast"}},
+		{name: "limit", window: time.Hour, limit: 2, want: []string{"old", "slower"}},
+		// The bucket overlaps the window but the event itself is older
+		{name: "event outside window", window: time.Minute, limit: 10, want: []string{"slow", "fast"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := s.SlowestSearches(context.Background(), tt.window, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(events))
+			for i, event := range events {
+				got[i] = event.Query
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SlowestSearches() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreSaveRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "analytics", "analytics.json")
+	s, _ := newTestStore(t, filePath, 24*time.Hour)
+	record(t, s,
+		model.SearchEvent{Query: "iphone", Results: 3, Latency: 10 * time.Millisecond, At: testStart},
+		model.SearchEvent{Query: "zapatilas runing", Filters: "condition=new", Latency: 20 * time.Millisecond, At: testStart.Add(-time.Hour)},
+	)
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _ := newTestStore(t, filePath, 24*time.Hour)
+
+	wantTop, _ := s.TopQueries(ctx, 24*time.Hour, 10)
+	gotTop, _ := loaded.TopQueries(ctx, 24*time.Hour, 10)
+	if len(gotTop) != 2 || !reflect.DeepEqual(gotTop, wantTop) {
+		t.Errorf("TopQueries() after reload = %+v, want %+v", gotTop, wantTop)
+	}
+
+	wantZero, _ := s.ZeroResultQueries(ctx, 24*time.Hour, 10)
+	gotZero, _ := loaded.ZeroResultQueries(ctx, 24*time.Hour, 10)
+	if len(gotZero) != 1 || !reflect.DeepEqual(gotZero, wantZero) {
+		t.Errorf("ZeroResultQueries() after reload = %+v, want %+v", gotZero, wantZero)
+	}
+
+	wantSlowest, _ := s.SlowestSearches(ctx, 24*time.Hour, 10)
+	gotSlowest, _ := loaded.SlowestSearches(ctx, 24*time.Hour, 10)
+	if len(gotSlowest) != 2 || !reflect.DeepEqual(gotSlowest, wantSlowest) {
+		t.Errorf("SlowestSearches() after reload = %+v, want %+v", gotSlowest, wantSlowest)
+	}
+}
+
+func TestStoreFailedSaveStaysPending(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "analytics.json")
+	s, _ := newTestStore(t, filePath, 24*time.Hour)
+	record(t, s, model.SearchEvent{Query: "iphone", Results: 3, At: testStart})
+
+	// A regular file where the directory should be makes the write fail
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s.filePath = filepath.Join(blocker, "analytics.json")
+	if err := s.Save(); err == nil {
+		t.Fatal("Save() to a broken path err = nil")
+	}
+
+	s.filePath = filePath
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _ := newTestStore(t, filePath, 24*time.Hour)
+	stats, _ := loaded.TopQueries(context.Background(), time.Hour, 10)
+	if got := queries(stats); !reflect.DeepEqual(got, []string{"iphone"}) {
+		t.Errorf("TopQueries() after retried save = %q, want [iphone]", got)
+	}
+}
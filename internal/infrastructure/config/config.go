@@ -1,14 +1,23 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Logger   LoggerConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Search     SearchConfig
+	Aggregator AggregatorConfig
+	Breaker    BreakerConfig
+	Retry      RetryConfig
+	Analytics  AnalyticsConfig
+	Admin      AdminConfig
+	Logger     LoggerConfig
 }
 
 type ServerConfig struct {
@@ -22,6 +31,94 @@ type DatabaseConfig struct {
 	SellersFile   string
 	ReviewsFile   string
 	QuestionsFile string
+	// ProductsReloadInterval: cada cuánto se relee ProductsFile si cambió,
+	// aplicando altas, cambios y bajas (0 desactiva la recarga).
+	ProductsReloadInterval time.Duration
+	// SavedSearchesFile guarda búsquedas guardadas y notificaciones.
+	SavedSearchesFile string
+}
+
+type SearchConfig struct {
+	TitleBoost       float64
+	BrandBoost       float64
+	ModelBoost       float64
+	CategoryBoost    float64
+	DescriptionBoost float64
+	AttributesBoost  float64
+	// FuzzyMinResults: si la búsqueda exacta trae menos resultados, se
+	// reintenta tolerando errores de tipeo. 0 lo desactiva.
+	FuzzyMinResults int
+	// AutoCorrect ejecuta la sugerencia ortográfica cuando la query no
+	// trae resultados, en lugar de solo devolverla.
+	AutoCorrect bool
+	// SynonymsFile es el diccionario de sinónimos; vacío lo desactiva. Se
+	// relee cada SynonymsReloadInterval si cambió (0 desactiva la recarga).
+	SynonymsFile           string
+	SynonymsReloadInterval time.Duration
+	// CursorSecret firma los cursores de paginación. Si está vacío se
+	// genera uno al iniciar y los cursores dejan de valer al reiniciar.
+	CursorSecret string
+	// HistogramBuckets es la cantidad de intervalos por defecto del
+	// histograma de precios.
+	HistogramBuckets int
+	// Marcadores que envuelven las coincidencias con highlight=true
+	HighlightPreTag  string
+	HighlightPostTag string
+}
+
+// AggregatorConfig fija el tiempo máximo de cada sección del detalle de
+// producto; la que vence se omite y se informa en degraded_sections.
+type AggregatorConfig struct {
+	SellerTimeout    time.Duration
+	ReviewsTimeout   time.Duration
+	QuestionsTimeout time.Duration
+	RelatedTimeout   time.Duration
+}
+
+// BreakerConfig aplica a los circuit breakers de los clientes de
+// vendedores, opiniones y preguntas.
+type BreakerConfig struct {
+	// Window es el período sobre el que se mide la tasa de fallas;
+	// MinRequests, cuántas llamadas tiene que haber para que cuente.
+	Window      time.Duration
+	MinRequests int
+	// FailureRate (0 a 1) abre el circuito al alcanzarse.
+	FailureRate float64
+	// OpenTimeout es cuánto queda abierto; después pasan HalfOpenRequests
+	// llamadas de prueba que, si salen bien, lo cierran.
+	OpenTimeout      time.Duration
+	HalfOpenRequests int
+}
+
+// RetryConfig aplica a los reintentos de los clientes de vendedores,
+// opiniones y preguntas. "No encontrado" nunca se reintenta.
+type RetryConfig struct {
+	// MaxAttempts incluye la llamada original; 1 desactiva los reintentos.
+	MaxAttempts int
+	// Espera exponencial desde InitialBackoff hasta MaxBackoff, variada en
+	// ± Jitter (fracción).
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	// Budget acota el tiempo total de los intentos, además del deadline
+	// de cada sección.
+	Budget time.Duration
+}
+
+type AnalyticsConfig struct {
+	// File persiste la analítica de búsqueda entre reinicios; se escribe
+	// cada FlushInterval y al apagar.
+	File          string
+	FlushInterval time.Duration
+	// Retention es cuánto se guarda y la ventana máxima consultable.
+	Retention time.Duration
+}
+
+type AdminConfig struct {
+	// Token protege /api/v1/admin con "Authorization: Bearer"; vacío
+	// deshabilita esas rutas (503).
+	Token string
 }
 
 type LoggerConfig struct {
@@ -36,11 +133,58 @@ func Load() *Config {
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 		},
 		Database: DatabaseConfig{
-			Type:          getEnv("DB_TYPE", "json"),
-			ProductsFile:  getEnv("PRODUCTS_FILE", "./data/products.json"),
-			SellersFile:   getEnv("SELLERS_FILE", "./data/sellers.json"),
-			ReviewsFile:   getEnv("REVIEWS_FILE", "./data/reviews.json"),
-			QuestionsFile: getEnv("QUESTIONS_FILE", "./data/questions.json"),
+			Type:                   getEnv("DB_TYPE", "json"),
+			ProductsFile:           getEnv("PRODUCTS_FILE", "./data/products.json"),
+			SellersFile:            getEnv("SELLERS_FILE", "./data/sellers.json"),
+			ReviewsFile:            getEnv("REVIEWS_FILE", "./data/reviews.json"),
+			QuestionsFile:          getEnv("QUESTIONS_FILE", "./data/questions.json"),
+			ProductsReloadInterval: getEnvAsDuration("PRODUCTS_RELOAD_INTERVAL", 30*time.Second),
+			SavedSearchesFile:      getEnv("SAVED_SEARCHES_FILE", "./state/saved_searches.json"),
+		},
+		Search: SearchConfig{
+			TitleBoost:             getEnvAsFloat("SEARCH_BOOST_TITLE", 3.0),
+			BrandBoost:             getEnvAsFloat("SEARCH_BOOST_BRAND", 2.5),
+			ModelBoost:             getEnvAsFloat("SEARCH_BOOST_MODEL", 2.0),
+			CategoryBoost:          getEnvAsFloat("SEARCH_BOOST_CATEGORY", 1.5),
+			DescriptionBoost:       getEnvAsFloat("SEARCH_BOOST_DESCRIPTION", 1.0),
+			AttributesBoost:        getEnvAsFloat("SEARCH_BOOST_ATTRIBUTES", 1.0),
+			FuzzyMinResults:        getEnvAsInt("SEARCH_FUZZY_MIN_RESULTS", 3),
+			AutoCorrect:            getEnvAsBool("SEARCH_AUTO_CORRECT", false),
+			SynonymsFile:           getEnv("SEARCH_SYNONYMS_FILE", "./data/synonyms.json"),
+			SynonymsReloadInterval: getEnvAsDuration("SEARCH_SYNONYMS_RELOAD_INTERVAL", 30*time.Second),
+			CursorSecret:           getEnv("SEARCH_CURSOR_SECRET", ""),
+			HistogramBuckets:       getEnvAsInt("SEARCH_HISTOGRAM_BUCKETS", 10),
+			HighlightPreTag:        getEnv("SEARCH_HIGHLIGHT_PRE_TAG", "<em>"),
+			HighlightPostTag:       getEnv("SEARCH_HIGHLIGHT_POST_TAG", "</em>"),
+		},
+		Aggregator: AggregatorConfig{
+			SellerTimeout:    getEnvAsDuration("AGGREGATOR_SELLER_TIMEOUT", 300*time.Millisecond),
+			ReviewsTimeout:   getEnvAsDuration("AGGREGATOR_REVIEWS_TIMEOUT", 300*time.Millisecond),
+			QuestionsTimeout: getEnvAsDuration("AGGREGATOR_QUESTIONS_TIMEOUT", 300*time.Millisecond),
+			RelatedTimeout:   getEnvAsDuration("AGGREGATOR_RELATED_TIMEOUT", 200*time.Millisecond),
+		},
+		Breaker: BreakerConfig{
+			Window:           getEnvAsDuration("BREAKER_WINDOW", 30*time.Second),
+			MinRequests:      getEnvAsInt("BREAKER_MIN_REQUESTS", 10),
+			FailureRate:      getEnvAsFloat("BREAKER_FAILURE_RATE", 0.5),
+			OpenTimeout:      getEnvAsDuration("BREAKER_OPEN_TIMEOUT", 15*time.Second),
+			HalfOpenRequests: getEnvAsInt("BREAKER_HALF_OPEN_REQUESTS", 3),
+		},
+		Retry: RetryConfig{
+			MaxAttempts:    getEnvAsInt("RETRY_MAX_ATTEMPTS", 3),
+			InitialBackoff: getEnvAsDuration("RETRY_INITIAL_BACKOFF", 20*time.Millisecond),
+			MaxBackoff:     getEnvAsDuration("RETRY_MAX_BACKOFF", 200*time.Millisecond),
+			Multiplier:     getEnvAsFloat("RETRY_MULTIPLIER", 2.0),
+			Jitter:         getEnvAsFloat("RETRY_JITTER", 0.2),
+			Budget:         getEnvAsDuration("RETRY_BUDGET", 250*time.Millisecond),
+		},
+		Analytics: AnalyticsConfig{
+			File:          getEnv("ANALYTICS_FILE", "./state/search_analytics.json"),
+			FlushInterval: getEnvAsDuration("ANALYTICS_FLUSH_INTERVAL", time.Minute),
+			Retention:     getEnvAsDuration("ANALYTICS_RETENTION", 7*24*time.Hour),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
 		},
 		Logger: LoggerConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -56,7 +200,14 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-/*
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.Atoi(valueStr); err == nil {
@@ -72,12 +223,46 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
-*/
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if value, err := time.ParseDuration(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
 
 func (c *Config) Validate() error {
 	// Add validation logic here
 	if c.Server.Port == "" {
 		log.Fatal("SERVER_PORT is required")
 	}
+	if c.Breaker.Window <= 0 {
+		return fmt.Errorf("BREAKER_WINDOW must be positive, got %s", c.Breaker.Window)
+	}
+	if c.Breaker.FailureRate <= 0 || c.Breaker.FailureRate > 1 {
+		return fmt.Errorf("BREAKER_FAILURE_RATE must be greater than 0 and at most 1, got %g", c.Breaker.FailureRate)
+	}
+	if c.Breaker.HalfOpenRequests < 1 {
+		return fmt.Errorf("BREAKER_HALF_OPEN_REQUESTS must be at least 1, got %d", c.Breaker.HalfOpenRequests)
+	}
+	if c.Retry.MaxAttempts < 1 {
+		return fmt.Errorf("RETRY_MAX_ATTEMPTS must be at least 1, got %d", c.Retry.MaxAttempts)
+	}
+	if c.Retry.InitialBackoff < 0 {
+		return fmt.Errorf("RETRY_INITIAL_BACKOFF must not be negative, got %s", c.Retry.InitialBackoff)
+	}
+	if c.Retry.MaxBackoff < 0 {
+		return fmt.Errorf("RETRY_MAX_BACKOFF must not be negative, got %s", c.Retry.MaxBackoff)
+	}
+	if !(c.Retry.Multiplier >= 1) {
+		return fmt.Errorf("RETRY_MULTIPLIER must be at least 1, got %g", c.Retry.Multiplier)
+	}
+	if !(c.Retry.Jitter >= 0 && c.Retry.Jitter <= 1) {
+		return fmt.Errorf("RETRY_JITTER must be between 0 and 1, got %g", c.Retry.Jitter)
+	}
+	if c.Retry.Budget < 0 {
+		return fmt.Errorf("RETRY_BUDGET must not be negative, got %s", c.Retry.Budget)
+	}
 	return nil
 }
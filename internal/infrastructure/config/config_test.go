@@ -0,0 +1,47 @@
+package config
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *RetryConfig)
+		wantErr string
+	}{
+		{name: "defaults", change: func(c *RetryConfig) {}},
+		{name: "retries disabled", change: func(c *RetryConfig) { c.MaxAttempts = 1 }},
+		{name: "no backoff", change: func(c *RetryConfig) { c.InitialBackoff, c.MaxBackoff = 0, 0 }},
+		{name: "constant backoff", change: func(c *RetryConfig) { c.Multiplier = 1 }},
+		{name: "zero attempts", change: func(c *RetryConfig) { c.MaxAttempts = 0 }, wantErr: "RETRY_MAX_ATTEMPTS"},
+		{name: "negative initial backoff", change: func(c *RetryConfig) { c.InitialBackoff = -time.Millisecond }, wantErr: "RETRY_INITIAL_BACKOFF"},
+		{name: "negative max backoff", change: func(c *RetryConfig) { c.MaxBackoff = -time.Millisecond }, wantErr: "RETRY_MAX_BACKOFF"},
+		{name: "shrinking backoff", change: func(c *RetryConfig) { c.Multiplier = 0.5 }, wantErr: "RETRY_MULTIPLIER"},
+		{name: "NaN multiplier", change: func(c *RetryConfig) { c.Multiplier = math.NaN() }, wantErr: "RETRY_MULTIPLIER"},
+		{name: "negative jitter", change: func(c *RetryConfig) { c.Jitter = -0.1 }, wantErr: "RETRY_JITTER"},
+		{name: "jitter above 1", change: func(c *RetryConfig) { c.Jitter = 1.5 }, wantErr: "RETRY_JITTER"},
+		{name: "negative budget", change: func(c *RetryConfig) { c.Budget = -time.Millisecond }, wantErr: "RETRY_BUDGET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Load()
+			tt.change(&c.Retry)
+
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() err = %v, want one about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
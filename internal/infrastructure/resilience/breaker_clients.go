@@ -0,0 +1,86 @@
+package resilience
+
+import (
+	"context"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+)
+
+// Decoradores que pasan cada llamada a un cliente por un CircuitBreaker.
+
+type breakerSellerClient struct {
+	next    port.SellerClient
+	breaker *CircuitBreaker
+}
+
+func NewBreakerSellerClient(next port.SellerClient, breaker *CircuitBreaker) port.SellerClient {
+	return &breakerSellerClient{next: next, breaker: breaker}
+}
+
+func (c *breakerSellerClient) GetByID(ctx context.Context, sellerID string) (*model.Seller, error) {
+	var seller *model.Seller
+	err := c.breaker.Execute(func() error {
+		var err error
+		seller, err = c.next.GetByID(ctx, sellerID)
+		return err
+	})
+	return seller, err
+}
+
+type breakerReviewClient struct {
+	next    port.ReviewClient
+	breaker *CircuitBreaker
+}
+
+func NewBreakerReviewClient(next port.ReviewClient, breaker *CircuitBreaker) port.ReviewClient {
+	return &breakerReviewClient{next: next, breaker: breaker}
+}
+
+func (c *breakerReviewClient) GetByProductID(ctx context.Context, productID string) ([]model.Review, error) {
+	var reviews []model.Review
+	err := c.breaker.Execute(func() error {
+		var err error
+		reviews, err = c.next.GetByProductID(ctx, productID)
+		return err
+	})
+	return reviews, err
+}
+
+func (c *breakerReviewClient) GetAverageRating(ctx context.Context, productID string) (float64, error) {
+	var rating float64
+	err := c.breaker.Execute(func() error {
+		var err error
+		rating, err = c.next.GetAverageRating(ctx, productID)
+		return err
+	})
+	return rating, err
+}
+
+func (c *breakerReviewClient) GetTotalCount(ctx context.Context, productID string) (int, error) {
+	var total int
+	err := c.breaker.Execute(func() error {
+		var err error
+		total, err = c.next.GetTotalCount(ctx, productID)
+		return err
+	})
+	return total, err
+}
+
+type breakerQuestionClient struct {
+	next    port.QuestionClient
+	breaker *CircuitBreaker
+}
+
+func NewBreakerQuestionClient(next port.QuestionClient, breaker *CircuitBreaker) port.QuestionClient {
+	return &breakerQuestionClient{next: next, breaker: breaker}
+}
+
+func (c *breakerQuestionClient) GetByProductID(ctx context.Context, productID string, limit int) ([]model.Question, error) {
+	var questions []model.Question
+	err := c.breaker.Execute(func() error {
+		var err error
+		questions, err = c.next.GetByProductID(ctx, productID, limit)
+		return err
+	})
+	return questions, err
+}
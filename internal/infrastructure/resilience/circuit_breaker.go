@@ -0,0 +1,238 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"sync"
+	"time"
+)
+
+// windowBuckets es en cuántos tramos se divide la ventana de fallas; al
+// avanzar el tiempo se descarta el tramo más viejo.
+const windowBuckets = 10
+
+const defaultFailureRate = 0.5
+
+// BreakerSettings configura cuándo se abre y cuándo se vuelve a cerrar un
+// circuit breaker.
+type BreakerSettings struct {
+	// Window es el período sobre el que se mide la tasa de fallas.
+	Window time.Duration
+	// MinRequests es cuántas llamadas tiene que haber en la ventana para
+	// que la tasa cuente; con menos nunca se abre.
+	MinRequests int
+	// FailureRate (0 a 1) abre el circuito al alcanzarse.
+	FailureRate float64
+	// OpenTimeout es cuánto queda abierto antes de probar de nuevo.
+	OpenTimeout time.Duration
+	// HalfOpenRequests es cuántas llamadas de prueba tienen que salir bien
+	// para cerrarlo; una que falle lo vuelve a abrir.
+	HalfOpenRequests int
+}
+
+type bucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+// CircuitBreaker corta las llamadas a un servicio cuya tasa de fallas
+// supera el umbral, para no seguir pagando su latencia, y cada
+// OpenTimeout deja pasar algunas de prueba.
+type CircuitBreaker struct {
+	name     string
+	settings BreakerSettings
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	state   model.CircuitState
+	since   time.Time
+	buckets [windowBuckets]bucket
+	// generation cambia con cada transición; descarta resultados de
+	// llamadas que empezaron en un estado anterior.
+	generation uint64
+	probes     int
+	successes  int
+}
+
+// NewCircuitBreaker corrige valores que lo dejarían trabado: pide al
+// menos una llamada de prueba, y una tasa de fallas fuera de (0, 1] pasa a
+// defaultFailureRate.
+func NewCircuitBreaker(name string, settings BreakerSettings, logger *slog.Logger) *CircuitBreaker {
+	settings.HalfOpenRequests = max(settings.HalfOpenRequests, 1)
+	if settings.FailureRate <= 0 || settings.FailureRate > 1 {
+		settings.FailureRate = defaultFailureRate
+	}
+
+	return &CircuitBreaker{
+		name:     name,
+		settings: settings,
+		logger:   logger,
+		now:      time.Now,
+		state:    model.CircuitClosed,
+		since:    time.Now(),
+	}
+}
+
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// Execute llama a fn si el circuito lo permite y registra el resultado. Si
+// está abierto devuelve un error que envuelve port.ErrCircuitOpen sin
+// llamarla.
+func (cb *CircuitBreaker) Execute(fn func() error) error {
+	generation, ok := cb.allow()
+	if !ok {
+		return fmt.Errorf("%s: %w", cb.name, port.ErrCircuitOpen)
+	}
+
+	err := fn()
+	cb.record(generation, err)
+	return err
+}
+
+func (cb *CircuitBreaker) allow() (uint64, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	if cb.state == model.CircuitOpen && now.Sub(cb.since) >= cb.settings.OpenTimeout {
+		cb.transition(model.CircuitHalfOpen, now)
+	}
+
+	switch cb.state {
+	case model.CircuitOpen:
+		return 0, false
+	case model.CircuitHalfOpen:
+		if cb.probes >= cb.settings.HalfOpenRequests {
+			return 0, false
+		}
+		cb.probes++
+	}
+	return cb.generation, true
+}
+
+func (cb *CircuitBreaker) record(generation uint64, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+
+	// A call canceled by its caller says nothing about the service: it is
+	// not counted, and a probe slot goes back for another call to prove it
+	if errors.Is(err, context.Canceled) {
+		if cb.state == model.CircuitHalfOpen {
+			cb.probes--
+		}
+		return
+	}
+
+	now := cb.now()
+	failed := isFailure(err)
+
+	switch cb.state {
+	case model.CircuitClosed:
+		b := cb.bucketAt(now)
+		b.requests++
+		if failed {
+			b.failures++
+		}
+		requests, failures := cb.counts(now)
+		if requests >= cb.settings.MinRequests && requests > 0 &&
+			float64(failures)/float64(requests) >= cb.settings.FailureRate {
+			cb.transition(model.CircuitOpen, now)
+		}
+	case model.CircuitHalfOpen:
+		if failed {
+			cb.transition(model.CircuitOpen, now)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.settings.HalfOpenRequests {
+			cb.transition(model.CircuitClosed, now)
+		}
+	}
+}
+
+// transition debe llamarse con mu tomado.
+func (cb *CircuitBreaker) transition(state model.CircuitState, now time.Time) {
+	requests, failures := cb.counts(now)
+	from := cb.state
+
+	cb.state = state
+	cb.since = now
+	cb.generation++
+	cb.probes = 0
+	cb.successes = 0
+	if state == model.CircuitClosed {
+		cb.buckets = [windowBuckets]bucket{}
+	}
+
+	attrs := []any{
+		"breaker", cb.name,
+		"from", from,
+		"to", state,
+	}
+	if state == model.CircuitOpen {
+		attrs = append(attrs, "requests", requests, "failures", failures, "retry_in", cb.settings.OpenTimeout)
+		cb.logger.Warn("Circuit breaker opened", attrs...)
+		return
+	}
+	cb.logger.Info("Circuit breaker state changed", attrs...)
+}
+
+// bucketAt devuelve el tramo de la ventana que corresponde a now,
+// vaciándolo si había quedado de una vuelta anterior.
+func (cb *CircuitBreaker) bucketAt(now time.Time) *bucket {
+	width := cb.bucketWidth()
+	start := now.Truncate(width)
+	b := &cb.buckets[int(start.UnixNano()/int64(width))%windowBuckets]
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	return b
+}
+
+// counts suma las llamadas de los tramos que siguen dentro de la ventana.
+func (cb *CircuitBreaker) counts(now time.Time) (requests, failures int) {
+	oldest := now.Truncate(cb.bucketWidth()).Add(-cb.settings.Window)
+	for _, b := range cb.buckets {
+		if b.start.After(oldest) {
+			requests += b.requests
+			failures += b.failures
+		}
+	}
+	return requests, failures
+}
+
+func (cb *CircuitBreaker) bucketWidth() time.Duration {
+	return max(cb.settings.Window/windowBuckets, time.Millisecond)
+}
+
+// Status devuelve el estado actual. Un circuito abierto cuyo tiempo de
+// espera ya pasó figura abierto hasta la próxima llamada.
+func (cb *CircuitBreaker) Status() model.CircuitStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	requests, failures := cb.counts(cb.now())
+	status := model.CircuitStatus{
+		Name:     cb.name,
+		State:    cb.state,
+		Since:    cb.since,
+		Requests: requests,
+		Failures: failures,
+	}
+	if requests > 0 {
+		status.FailureRate = float64(failures) / float64(requests)
+	}
+	return status
+}
@@ -0,0 +1,270 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+	"testing"
+	"time"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	discardLogs = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+// fakeClock es un reloj que solo avanza cuando el test lo pide.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(settings BreakerSettings) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)}
+	cb := NewCircuitBreaker("test", settings, discardLogs)
+	cb.now = clock.now
+	return cb, clock
+}
+
+var testSettings = BreakerSettings{
+	Window:           10 * time.Second,
+	MinRequests:      4,
+	FailureRate:      0.5,
+	OpenTimeout:      5 * time.Second,
+	HalfOpenRequests: 2,
+}
+
+func call(cb *CircuitBreaker, err error) error {
+	return cb.Execute(func() error { return err })
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	type step struct {
+		advance time.Duration
+		err     error // resultado de la llamada
+		// rejected indica que Execute no debe llamar a fn
+		rejected bool
+		want     model.CircuitState
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "stays closed below min requests",
+			steps: []step{
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+			},
+		},
+		{
+			name: "stays closed below failure rate",
+			steps: []step{
+				{err: errBoom, want: model.CircuitClosed},
+				{err: nil, want: model.CircuitClosed},
+				{err: nil, want: model.CircuitClosed},
+				{err: nil, want: model.CircuitClosed},
+			},
+		},
+		{
+			name: "closed to open to half-open to closed",
+			steps: []step{
+				{err: nil, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+				{err: nil, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitOpen},
+				{err: nil, rejected: true, want: model.CircuitOpen},
+				{advance: 4 * time.Second, err: nil, rejected: true, want: model.CircuitOpen},
+				{advance: time.Second, err: nil, want: model.CircuitHalfOpen},
+				{err: nil, want: model.CircuitClosed},
+				// The old failures were forgotten on close
+				{err: errBoom, want: model.CircuitClosed},
+			},
+		},
+		{
+			name: "half-open failure opens again",
+			steps: []step{
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitOpen},
+				{advance: 5 * time.Second, err: nil, want: model.CircuitHalfOpen},
+				{err: errBoom, want: model.CircuitOpen},
+				{err: nil, rejected: true, want: model.CircuitOpen},
+			},
+		},
+		{
+			name: "not found is not a failure",
+			steps: []step{
+				{err: port.ErrSellerNotFound, want: model.CircuitClosed},
+				{err: port.ErrReviewsNotFound, want: model.CircuitClosed},
+				{err: fmt.Errorf("wrapped: %w", port.ErrSellerNotFound), want: model.CircuitClosed},
+				{err: port.ErrQuestionsNotFound, want: model.CircuitClosed},
+				{err: fmt.Errorf("wrapped: %w", port.ErrReviewsNotFound), want: model.CircuitClosed},
+			},
+		},
+		{
+			name: "failures slide out of the window",
+			steps: []step{
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+				{err: errBoom, want: model.CircuitClosed},
+				{advance: 11 * time.Second, err: errBoom, want: model.CircuitClosed},
+			},
+		},
+		{
+			name: "timeouts are failures",
+			steps: []step{
+				{err: context.DeadlineExceeded, want: model.CircuitClosed},
+				{err: context.DeadlineExceeded, want: model.CircuitClosed},
+				{err: context.DeadlineExceeded, want: model.CircuitClosed},
+				{err: context.DeadlineExceeded, want: model.CircuitOpen},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb, clock := newTestBreaker(testSettings)
+
+			for i, s := range tt.steps {
+				clock.advance(s.advance)
+
+				called := false
+				err := cb.Execute(func() error {
+					called = true
+					return s.err
+				})
+
+				if called == s.rejected {
+					t.Fatalf("step %d: called = %v, want %v", i, called, !s.rejected)
+				}
+				if s.rejected && !errors.Is(err, port.ErrCircuitOpen) {
+					t.Fatalf("step %d: err = %v, want ErrCircuitOpen", i, err)
+				}
+				if got := cb.Status().State; got != s.want {
+					t.Fatalf("step %d: state = %s, want %s", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func openBreaker(t *testing.T, cb *CircuitBreaker, clock *fakeClock) {
+	t.Helper()
+	for range cb.settings.MinRequests {
+		call(cb, errBoom)
+	}
+	if got := cb.Status().State; got != model.CircuitOpen {
+		t.Fatalf("state = %s, want open", got)
+	}
+	clock.advance(cb.settings.OpenTimeout)
+}
+
+func TestCircuitBreakerHalfOpenLimitsProbes(t *testing.T) {
+	cb, clock := newTestBreaker(testSettings)
+	openBreaker(t, cb, clock)
+
+	// While both probes are in flight any other call is rejected
+	var nested error
+	err := cb.Execute(func() error {
+		return cb.Execute(func() error {
+			nested = call(cb, nil)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("probe err = %v", err)
+	}
+	if !errors.Is(nested, port.ErrCircuitOpen) {
+		t.Errorf("third call err = %v, want ErrCircuitOpen", nested)
+	}
+	if got := cb.Status().State; got != model.CircuitClosed {
+		t.Errorf("state = %s, want closed after both probes succeeded", got)
+	}
+}
+
+func TestCircuitBreakerCanceledProbeIsNeutral(t *testing.T) {
+	cb, clock := newTestBreaker(testSettings)
+	openBreaker(t, cb, clock)
+
+	// Canceled probes neither close nor reopen, and free their slot
+	for range 5 {
+		if err := call(cb, context.Canceled); !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want the call to run and return context.Canceled", err)
+		}
+		if got := cb.Status().State; got != model.CircuitHalfOpen {
+			t.Fatalf("state = %s, want half_open", got)
+		}
+	}
+
+	call(cb, nil)
+	if got := cb.Status().State; got != model.CircuitHalfOpen {
+		t.Fatalf("state = %s, want half_open after one of two probes", got)
+	}
+	call(cb, nil)
+	if got := cb.Status().State; got != model.CircuitClosed {
+		t.Fatalf("state = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerCanceledCallsAreNotCounted(t *testing.T) {
+	cb, _ := newTestBreaker(testSettings)
+
+	for range 10 {
+		call(cb, context.Canceled)
+	}
+
+	status := cb.Status()
+	if status.State != model.CircuitClosed || status.Requests != 0 {
+		t.Errorf("status = %+v, want closed with no requests counted", status)
+	}
+}
+
+func TestCircuitBreakerIgnoresResultsFromPreviousState(t *testing.T) {
+	cb, clock := newTestBreaker(testSettings)
+
+	// A call that started while closed finishes after the circuit opened
+	err := cb.Execute(func() error {
+		openBreaker(t, cb, clock)
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v", err)
+	}
+
+	call(cb, nil)
+	call(cb, nil)
+	if got := cb.Status().State; got != model.CircuitClosed {
+		t.Errorf("state = %s, want closed: the stale failure must not reopen it", got)
+	}
+}
+
+func TestNewCircuitBreakerFixesSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		settings     BreakerSettings
+		wantProbes   int
+		wantFailRate float64
+	}{
+		{name: "valid", settings: testSettings, wantProbes: 2, wantFailRate: 0.5},
+		{name: "no probes", settings: BreakerSettings{Window: time.Second, FailureRate: 0.3}, wantProbes: 1, wantFailRate: 0.3},
+		{name: "zero failure rate", settings: BreakerSettings{Window: time.Second, HalfOpenRequests: 3}, wantProbes: 3, wantFailRate: defaultFailureRate},
+		{name: "failure rate above one", settings: BreakerSettings{Window: time.Second, FailureRate: 2, HalfOpenRequests: -1}, wantProbes: 1, wantFailRate: defaultFailureRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := NewCircuitBreaker("test", tt.settings, discardLogs)
+			if cb.settings.HalfOpenRequests != tt.wantProbes || cb.settings.FailureRate != tt.wantFailRate {
+				t.Errorf("settings = %+v, want %d probes and failure rate %g", cb.settings, tt.wantProbes, tt.wantFailRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"meli-product-api/internal/domain/port"
+)
+
+// isFailure reporta si err indica que el servicio está fallando. Un
+// recurso inexistente es una respuesta válida, y un request cancelado por
+// el cliente no dice nada del servicio.
+func isFailure(err error) bool {
+	return err != nil &&
+		!isNotFound(err) &&
+		!errors.Is(err, context.Canceled)
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, port.ErrSellerNotFound) ||
+		errors.Is(err, port.ErrReviewsNotFound) ||
+		errors.Is(err, port.ErrQuestionsNotFound)
+}
+
+// isRetryable reporta si vale la pena repetir la llamada: fallas del
+// servicio, salvo un circuito abierto, que rechazaría el reintento igual.
+func isRetryable(err error) bool {
+	return isFailure(err) && !errors.Is(err, port.ErrCircuitOpen)
+}
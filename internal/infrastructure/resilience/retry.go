@@ -0,0 +1,100 @@
+package resilience
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy define cuántas veces y con qué espera se repite una llamada
+// fallida.
+type RetryPolicy struct {
+	// MaxAttempts cuenta la llamada original; 1 desactiva los reintentos.
+	MaxAttempts int
+	// La espera arranca en InitialBackoff y se multiplica por Multiplier
+	// en cada reintento, hasta MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter (0 a 1) varía cada espera en ± esa fracción, para que los
+	// requests que fallaron juntos no reintenten juntos.
+	Jitter float64
+	// Budget acota el tiempo total de todos los intentos, sin pasar el
+	// deadline de ctx. 0 deja solo el de ctx.
+	Budget time.Duration
+	// Retryable decide qué errores se reintentan; nil reintenta fallas del
+	// servicio y nunca "no encontrado".
+	Retryable func(error) bool
+}
+
+// Retrier repite llamadas a un servicio según una RetryPolicy.
+type Retrier struct {
+	name   string
+	policy RetryPolicy
+	logger *slog.Logger
+}
+
+func NewRetrier(name string, policy RetryPolicy, logger *slog.Logger) *Retrier {
+	if policy.Retryable == nil {
+		policy.Retryable = isRetryable
+	}
+	return &Retrier{
+		name:   name,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+// Do llama a fn hasta que salga bien, devuelva un error no reintentable o
+// se agoten los intentos. No espera un backoff que terminaría después del
+// deadline: devuelve el último error.
+func (r *Retrier) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.policy.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.policy.Budget)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= r.policy.MaxAttempts || ctx.Err() != nil || !r.policy.Retryable(err) {
+			return err
+		}
+
+		backoff := r.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			return err
+		}
+
+		r.logger.Warn("Retrying downstream call",
+			"client", r.name,
+			"attempt", attempt+1,
+			"backoff_ms", backoff.Milliseconds(),
+			"error", err,
+		)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff es la espera antes del intento attempt+1.
+func (r *Retrier) backoff(attempt int) time.Duration {
+	d := float64(r.policy.InitialBackoff)
+	for range attempt - 1 {
+		d *= r.policy.Multiplier
+	}
+	if r.policy.MaxBackoff > 0 {
+		d = min(d, float64(r.policy.MaxBackoff))
+	}
+	if r.policy.Jitter > 0 {
+		d *= 1 + r.policy.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,87 @@
+package resilience
+
+import (
+	"context"
+	"meli-product-api/internal/domain/model"
+	"meli-product-api/internal/domain/port"
+)
+
+// Decoradores que reintentan las llamadas fallidas a un cliente con un
+// Retrier.
+
+type retrySellerClient struct {
+	next    port.SellerClient
+	retrier *Retrier
+}
+
+func NewRetrySellerClient(next port.SellerClient, retrier *Retrier) port.SellerClient {
+	return &retrySellerClient{next: next, retrier: retrier}
+}
+
+func (c *retrySellerClient) GetByID(ctx context.Context, sellerID string) (*model.Seller, error) {
+	var seller *model.Seller
+	err := c.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		seller, err = c.next.GetByID(ctx, sellerID)
+		return err
+	})
+	return seller, err
+}
+
+type retryReviewClient struct {
+	next    port.ReviewClient
+	retrier *Retrier
+}
+
+func NewRetryReviewClient(next port.ReviewClient, retrier *Retrier) port.ReviewClient {
+	return &retryReviewClient{next: next, retrier: retrier}
+}
+
+func (c *retryReviewClient) GetByProductID(ctx context.Context, productID string) ([]model.Review, error) {
+	var reviews []model.Review
+	err := c.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		reviews, err = c.next.GetByProductID(ctx, productID)
+		return err
+	})
+	return reviews, err
+}
+
+func (c *retryReviewClient) GetAverageRating(ctx context.Context, productID string) (float64, error) {
+	var rating float64
+	err := c.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		rating, err = c.next.GetAverageRating(ctx, productID)
+		return err
+	})
+	return rating, err
+}
+
+func (c *retryReviewClient) GetTotalCount(ctx context.Context, productID string) (int, error) {
+	var total int
+	err := c.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		total, err = c.next.GetTotalCount(ctx, productID)
+		return err
+	})
+	return total, err
+}
+
+type retryQuestionClient struct {
+	next    port.QuestionClient
+	retrier *Retrier
+}
+
+func NewRetryQuestionClient(next port.QuestionClient, retrier *Retrier) port.QuestionClient {
+	return &retryQuestionClient{next: next, retrier: retrier}
+}
+
+func (c *retryQuestionClient) GetByProductID(ctx context.Context, productID string, limit int) ([]model.Question, error) {
+	var questions []model.Question
+	err := c.retrier.Do(ctx, func(ctx context.Context) error {
+		var err error
+		questions, err = c.next.GetByProductID(ctx, productID, limit)
+		return err
+	})
+	return questions, err
+}
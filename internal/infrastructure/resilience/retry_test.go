@@ -0,0 +1,146 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meli-product-api/internal/domain/port"
+	"testing"
+	"time"
+)
+
+var testPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Multiplier:     2,
+}
+
+func TestRetrierDo(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error // resultado de cada intento; después, éxito
+		wantAttempts int
+		wantErr      error
+	}{
+		{name: "success on first attempt", wantAttempts: 1},
+		{name: "success after transient errors", errs: []error{errBoom, errBoom}, wantAttempts: 3},
+		{name: "gives up after max attempts", errs: []error{errBoom, errBoom, errBoom, errBoom}, wantAttempts: 3, wantErr: errBoom},
+		{name: "not found is not retried", errs: []error{port.ErrSellerNotFound}, wantAttempts: 1, wantErr: port.ErrSellerNotFound},
+		{name: "reviews not found is not retried", errs: []error{fmt.Errorf("reviews: %w", port.ErrReviewsNotFound)}, wantAttempts: 1, wantErr: port.ErrReviewsNotFound},
+		{name: "questions not found is not retried", errs: []error{port.ErrQuestionsNotFound}, wantAttempts: 1, wantErr: port.ErrQuestionsNotFound},
+		{name: "open circuit is not retried", errs: []error{fmt.Errorf("seller: %w", port.ErrCircuitOpen)}, wantAttempts: 1, wantErr: port.ErrCircuitOpen},
+		{name: "canceled call is not retried", errs: []error{context.Canceled}, wantAttempts: 1, wantErr: context.Canceled},
+		{name: "timeouts are retried", errs: []error{context.DeadlineExceeded}, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRetrier("test", testPolicy, discardLogs)
+
+			attempts := 0
+			err := r.Do(context.Background(), func(context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetrierStopsWhenContextEnds(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		ctx    func() (context.Context, context.CancelFunc)
+		// cancelOn cancela ctx durante ese intento; 0 no cancela
+		cancelOn int
+	}{
+		{
+			name:     "canceled by the caller",
+			policy:   testPolicy,
+			ctx:      func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			cancelOn: 1,
+		},
+		{
+			name:   "backoff would pass the deadline",
+			policy: RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, Multiplier: 2},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 100*time.Millisecond)
+			},
+		},
+		{
+			name:   "backoff would pass the budget",
+			policy: RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, Multiplier: 2, Budget: 100 * time.Millisecond},
+			ctx:    func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			r := NewRetrier("test", tt.policy, discardLogs)
+
+			start := time.Now()
+			attempts := 0
+			err := r.Do(ctx, func(context.Context) error {
+				attempts++
+				if attempts == tt.cancelOn {
+					cancel()
+				}
+				return errBoom
+			})
+
+			if attempts != 1 {
+				t.Errorf("attempts = %d, want 1", attempts)
+			}
+			if !errors.Is(err, errBoom) {
+				t.Errorf("err = %v, want the last call error", err)
+			}
+			if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+				t.Errorf("Do took %v, want it to return without waiting", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetrierBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		{name: "first retry", policy: testPolicy, attempt: 1, min: time.Millisecond, max: time.Millisecond},
+		{name: "grows by multiplier", policy: testPolicy, attempt: 3, min: 4 * time.Millisecond, max: 4 * time.Millisecond},
+		{name: "capped at max backoff", policy: testPolicy, attempt: 10, min: 5 * time.Millisecond, max: 5 * time.Millisecond},
+		{
+			name:    "jitter stays within bounds",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2, Jitter: 0.2},
+			attempt: 2,
+			min:     160 * time.Millisecond,
+			max:     240 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRetrier("test", tt.policy, discardLogs)
+			for range 100 {
+				if got := r.backoff(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
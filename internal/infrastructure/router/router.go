@@ -9,7 +9,15 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func NewRouter(productHandler *handler.ProductHandler, logger *slog.Logger) *mux.Router {
+func NewRouter(
+	productHandler *handler.ProductHandler,
+	sellerHandler *handler.SellerHandler,
+	analyticsHandler *handler.SearchAnalyticsHandler,
+	breakerHandler *handler.CircuitBreakerHandler,
+	savedSearchHandler *handler.SavedSearchHandler,
+	adminToken string,
+	logger *slog.Logger,
+) *mux.Router {
 	r := mux.NewRouter()
 
 	// Global middlewares
@@ -20,10 +28,29 @@ func NewRouter(productHandler *handler.ProductHandler, logger *slog.Logger) *mux
 	// API routes
 	api := r.PathPrefix("/api/v1").Subrouter()
 
-	// Product routes
-	api.HandleFunc("/products/{id}", productHandler.GetProductDetails).Methods(http.MethodGet)
+	// Product routes (static paths first so {id} doesn't shadow them)
 	api.HandleFunc("/products/search", productHandler.SearchProducts).Methods(http.MethodGet)
+	api.HandleFunc("/products/suggest", productHandler.SuggestProducts).Methods(http.MethodGet)
 	api.HandleFunc("/products/health", productHandler.HealthCheck).Methods(http.MethodGet)
+	api.HandleFunc("/products/{id}", productHandler.GetProductDetails).Methods(http.MethodGet)
+
+	// Seller routes
+	api.HandleFunc("/sellers/{id}", sellerHandler.GetSeller).Methods(http.MethodGet)
+	api.HandleFunc("/sellers/{id}/products", sellerHandler.ListSellerProducts).Methods(http.MethodGet)
+
+	// Saved searches, scoped to the X-User-ID header
+	api.HandleFunc("/saved-searches", savedSearchHandler.CreateSavedSearch).Methods(http.MethodPost)
+	api.HandleFunc("/saved-searches", savedSearchHandler.ListSavedSearches).Methods(http.MethodGet)
+	api.HandleFunc("/saved-searches/{id}", savedSearchHandler.DeleteSavedSearch).Methods(http.MethodDelete)
+	api.HandleFunc("/notifications", savedSearchHandler.ListNotifications).Methods(http.MethodGet)
+
+	// Admin routes
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.AdminAuth(adminToken))
+	admin.HandleFunc("/search/top-queries", analyticsHandler.TopQueries).Methods(http.MethodGet)
+	admin.HandleFunc("/search/zero-results", analyticsHandler.ZeroResultQueries).Methods(http.MethodGet)
+	admin.HandleFunc("/search/slowest", analyticsHandler.SlowestSearches).Methods(http.MethodGet)
+	admin.HandleFunc("/circuit-breakers", breakerHandler.ListCircuitBreakers).Methods(http.MethodGet)
 
 	// Root health check
 	r.HandleFunc("/health", productHandler.HealthCheck).Methods(http.MethodGet)
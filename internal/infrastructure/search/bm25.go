@@ -0,0 +1,89 @@
+package search
+
+import "math"
+
+const (
+	defaultK1 = 1.2
+	defaultB  = 0.75
+)
+
+// DefaultBoosts prioriza coincidencias en el título por sobre la marca, el
+// modelo, la categoría y, por último, la descripción y los atributos.
+func DefaultBoosts() map[Field]float64 {
+	return map[Field]float64{
+		FieldTitle:       3.0,
+		FieldBrand:       2.5,
+		FieldModel:       2.0,
+		FieldCategory:    1.5,
+		FieldDescription: 1.0,
+		FieldAttributes:  1.0,
+	}
+}
+
+// Scorer calcula relevancia BM25 por campo y suma los resultados
+// ponderados por el boost de cada campo.
+type Scorer struct {
+	K1     float64
+	B      float64
+	Boosts map[Field]float64
+}
+
+func NewScorer(boosts map[Field]float64) *Scorer {
+	if boosts == nil {
+		boosts = DefaultBoosts()
+	}
+
+	return &Scorer{
+		K1:     defaultK1,
+		B:      defaultB,
+		Boosts: boosts,
+	}
+}
+
+// Score devuelve el puntaje de cada documento de docs (mismo orden) para
+// los términos dados. Solo recorre las posting lists de esos términos.
+func (s *Scorer) Score(ix *Index, terms []string, docs []int) []float64 {
+	scores := make([]float64, len(docs))
+	if len(docs) == 0 || ix.live == 0 {
+		return scores
+	}
+
+	slot := make(map[int]int, len(docs))
+	for i, d := range docs {
+		slot[d] = i
+	}
+
+	n := float64(ix.live)
+	for _, field := range Fields {
+		boost := s.Boosts[field]
+		if boost == 0 || ix.totals[field] == 0 {
+			continue
+		}
+
+		avgLen := float64(ix.totals[field]) / n
+		lengths := ix.lengths[field]
+
+		for _, term := range terms {
+			list := ix.postings[field][term]
+			if len(list) == 0 {
+				continue
+			}
+
+			df := float64(len(list))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+			for _, p := range list {
+				i, ok := slot[p.Doc]
+				if !ok {
+					continue
+				}
+
+				tf := float64(len(p.Positions))
+				norm := 1 - s.B + s.B*float64(lengths[p.Doc])/avgLen
+				scores[i] += boost * idf * tf * (s.K1 + 1) / (tf + s.K1*norm)
+			}
+		}
+	}
+
+	return scores
+}
@@ -0,0 +1,162 @@
+package search
+
+import (
+	"meli-product-api/internal/domain/query"
+	"sort"
+)
+
+// Evaluate resuelve el árbol de la query contra el índice y devuelve los
+// ordinales que cumplen, ordenados. Con fuzzy, cada palabra suelta también
+// acepta los términos a pocos errores de tipeo (las frases no).
+func (ix *Index) Evaluate(node query.Node, fuzzy bool) []int {
+	switch n := node.(type) {
+	case *query.Term:
+		fields := fieldsFor(n.Field)
+		if len(n.Terms) == 1 && !n.Phrase {
+			group := []string{n.Terms[0]}
+			if fuzzy {
+				group = append(group, ix.Expand(n.Terms[0], fields)...)
+			}
+			return ix.docsWithAny(group, fields)
+		}
+		// A quoted phrase, or a word the analyzer split ("wi-fi")
+		return ix.matchPhrase(n.Terms, n.Positions, fields)
+
+	case *query.And:
+		var include, exclude [][]int
+		for _, c := range n.Children {
+			if not, ok := c.(*query.Not); ok {
+				exclude = append(exclude, ix.Evaluate(not.Child, false))
+				continue
+			}
+			docs := ix.Evaluate(c, fuzzy)
+			if len(docs) == 0 {
+				return nil
+			}
+			include = append(include, docs)
+		}
+		if len(include) == 0 {
+			return nil
+		}
+
+		// Intersecting from the smallest set keeps intermediate results short
+		sort.Slice(include, func(i, j int) bool { return len(include[i]) < len(include[j]) })
+
+		result := include[0]
+		for _, set := range include[1:] {
+			result = intersect(result, set)
+		}
+		for _, set := range exclude {
+			result = subtract(result, set)
+		}
+		return result
+
+	case *query.Or:
+		var docs []int
+		for _, c := range n.Children {
+			docs = append(docs, ix.Evaluate(c, fuzzy)...)
+		}
+		return sortUnique(docs)
+	}
+
+	return nil
+}
+
+// Expansions devuelve las correcciones fuzzy de los términos dados, sin
+// repetir, para puntuarlas aparte.
+func (ix *Index) Expansions(terms []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, term := range terms {
+		for _, e := range ix.Expand(term, Fields) {
+			if !seen[e] {
+				seen[e] = true
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+func fieldsFor(name string) []Field {
+	if name == "" {
+		return Fields
+	}
+	return []Field{Field(name)}
+}
+
+// matchPhrase busca documentos donde los términos aparecen en el mismo
+// campo y a las distancias relativas indicadas por offsets.
+func (ix *Index) matchPhrase(terms []string, offsets []int, fields []Field) []int {
+	var docs []int
+
+	for _, field := range fields {
+		lists := make([][]Posting, len(terms))
+		for i, term := range terms {
+			lists[i] = ix.postings[field][term]
+			if len(lists[i]) == 0 {
+				lists = nil
+				break
+			}
+		}
+		if lists == nil {
+			continue
+		}
+
+		for _, first := range lists[0] {
+			if phraseAt(first, lists[1:], offsets[1:]) {
+				docs = append(docs, first.Doc)
+			}
+		}
+	}
+
+	if len(fields) > 1 {
+		docs = sortUnique(docs)
+	}
+	return docs
+}
+
+func phraseAt(first Posting, rest [][]Posting, offsets []int) bool {
+	others := make([]Posting, len(rest))
+	for i, list := range rest {
+		j := sort.Search(len(list), func(j int) bool { return list[j].Doc >= first.Doc })
+		if j == len(list) || list[j].Doc != first.Doc {
+			return false
+		}
+		others[i] = list[j]
+	}
+
+	for _, start := range first.Positions {
+		ok := true
+		for i, p := range others {
+			if !containsPosition(p.Positions, start+offsets[i]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPosition(positions []int, want int) bool {
+	i := sort.SearchInts(positions, want)
+	return i < len(positions) && positions[i] == want
+}
+
+func subtract(a, b []int) []int {
+	out := make([]int, 0, len(a))
+	j := 0
+	for _, d := range a {
+		for j < len(b) && b[j] < d {
+			j++
+		}
+		if j < len(b) && b[j] == d {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package search
+
+import "unicode/utf8"
+
+// FuzzyPenalty pondera el puntaje aportado por términos corregidos, para que
+// una coincidencia aproximada nunca pese lo mismo que una exacta.
+const FuzzyPenalty = 0.5
+
+// MaxEdits define cuántos errores se toleran según el largo del término:
+// ninguno para términos cortos (demasiados falsos positivos), uno para
+// términos medianos y dos para los largos.
+func MaxEdits(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Expand devuelve los términos del diccionario (de cualquier campo) que
+// están a distancia de edición entre 1 y MaxEdits(term).
+func (ix *Index) Expand(term string, fields []Field) []string {
+	maxEdits := MaxEdits(term)
+	if maxEdits == 0 {
+		return nil
+	}
+
+	length := utf8.RuneCountInString(term)
+	seen := make(map[string]bool)
+	var out []string
+
+	for _, field := range fields {
+		for candidate := range ix.postings[field] {
+			if seen[candidate] || candidate == term {
+				continue
+			}
+			seen[candidate] = true
+
+			if abs(utf8.RuneCountInString(candidate)-length) > maxEdits {
+				continue
+			}
+			if editDistance(term, candidate, maxEdits) <= maxEdits {
+				out = append(out, candidate)
+			}
+		}
+	}
+
+	return out
+}
+
+// editDistance calcula la distancia de Damerau-Levenshtein restringida
+// (transposiciones adyacentes cuentan como un solo error). Corta apenas
+// la distancia supera max y en ese caso devuelve max+1.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		rowMin := rows[i][0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+
+			rows[i][j] = d
+			rowMin = min(rowMin, d)
+		}
+
+		if rowMin > max {
+			return max + 1
+		}
+	}
+
+	return rows[len(ra)][len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,247 @@
+package search
+
+import (
+	"meli-product-api/internal/domain/analysis"
+	"slices"
+	"sort"
+)
+
+type Field string
+
+const (
+	FieldTitle       Field = "title"
+	FieldBrand       Field = "brand"
+	FieldModel       Field = "model"
+	FieldCategory    Field = "category"
+	FieldDescription Field = "description"
+	FieldAttributes  Field = "attributes"
+)
+
+// Fields son los campos indexados, en orden de importancia.
+var Fields = []Field{
+	FieldTitle,
+	FieldBrand,
+	FieldModel,
+	FieldCategory,
+	FieldDescription,
+	FieldAttributes,
+}
+
+// Document es la vista indexable de un producto.
+type Document struct {
+	ID     string
+	Fields map[Field]string
+}
+
+// Posting indica en qué posiciones aparece un término dentro de un documento.
+type Posting struct {
+	Doc       int
+	Positions []int
+}
+
+// Index es un índice invertido en memoria con posting lists por campo,
+// ordenadas por el ordinal interno de cada documento. Un documento
+// actualizado conserva su ordinal y uno nuevo ocupa el de alguno eliminado,
+// así el índice no crece con las actualizaciones.
+//
+// Index no es seguro para uso concurrente; el llamador debe sincronizar.
+type Index struct {
+	analyzer *analysis.Analyzer
+	postings map[Field]map[string][]Posting
+	ids      []string
+	ordinals map[string]int
+	free     []int // ordinales de documentos eliminados
+	docTerms []map[Field][]string
+	lengths  map[Field][]int
+	totals   map[Field]int
+	live     int
+}
+
+func NewIndex(analyzer *analysis.Analyzer) *Index {
+	postings := make(map[Field]map[string][]Posting, len(Fields))
+	for _, f := range Fields {
+		postings[f] = make(map[string][]Posting)
+	}
+
+	return &Index{
+		analyzer: analyzer,
+		postings: postings,
+		ordinals: make(map[string]int),
+		lengths:  make(map[Field][]int, len(Fields)),
+		totals:   make(map[Field]int, len(Fields)),
+	}
+}
+
+// Add indexa el documento y devuelve su ordinal. Si ya existía un documento
+// con el mismo ID se reemplaza en el mismo ordinal.
+func (ix *Index) Add(doc Document) int {
+	ord, ok := ix.ordinals[doc.ID]
+	if ok {
+		ix.unindex(ord)
+	} else {
+		ord = ix.allocate()
+		ix.ids[ord] = doc.ID
+		ix.ordinals[doc.ID] = ord
+		ix.live++
+	}
+
+	terms := make(map[Field][]string, len(doc.Fields))
+	for field, text := range doc.Fields {
+		fieldPostings, ok := ix.postings[field]
+		if !ok {
+			continue
+		}
+
+		tokens := ix.analyzer.Analyze(text)
+		ix.lengths[field][ord] = len(tokens)
+		ix.totals[field] += len(tokens)
+
+		positions := make(map[string][]int)
+		var order []string
+		for _, tok := range tokens {
+			if _, seen := positions[tok.Term]; !seen {
+				order = append(order, tok.Term)
+			}
+			positions[tok.Term] = append(positions[tok.Term], tok.Position)
+		}
+
+		for _, term := range order {
+			fieldPostings[term] = insertPosting(fieldPostings[term], Posting{Doc: ord, Positions: positions[term]})
+		}
+		terms[field] = order
+	}
+	ix.docTerms[ord] = terms
+
+	return ord
+}
+
+// allocate devuelve un ordinal libre, reutilizando el de un documento
+// eliminado si lo hay.
+func (ix *Index) allocate() int {
+	if n := len(ix.free); n > 0 {
+		ord := ix.free[n-1]
+		ix.free = ix.free[:n-1]
+		return ord
+	}
+
+	ix.ids = append(ix.ids, "")
+	ix.docTerms = append(ix.docTerms, nil)
+	for _, field := range Fields {
+		ix.lengths[field] = append(ix.lengths[field], 0)
+	}
+	return len(ix.ids) - 1
+}
+
+// insertPosting agrega p a list manteniendo el orden por ordinal. Los
+// documentos nuevos suelen ir al final, así que ese caso no busca.
+func insertPosting(list []Posting, p Posting) []Posting {
+	if n := len(list); n == 0 || list[n-1].Doc < p.Doc {
+		return append(list, p)
+	}
+
+	i := sort.Search(len(list), func(i int) bool { return list[i].Doc >= p.Doc })
+	return slices.Insert(list, i, p)
+}
+
+// Remove saca el documento de todas las posting lists. Su ordinal queda
+// libre para el próximo documento nuevo.
+func (ix *Index) Remove(id string) bool {
+	ord, ok := ix.ordinals[id]
+	if !ok {
+		return false
+	}
+
+	ix.unindex(ord)
+	delete(ix.ordinals, id)
+	ix.ids[ord] = ""
+	ix.free = append(ix.free, ord)
+	ix.live--
+
+	return true
+}
+
+// unindex quita los términos del ordinal ord de las posting lists y de
+// los largos por campo.
+func (ix *Index) unindex(ord int) {
+	for field, terms := range ix.docTerms[ord] {
+		fieldPostings := ix.postings[field]
+		for _, term := range terms {
+			list := fieldPostings[term]
+			i := sort.Search(len(list), func(i int) bool { return list[i].Doc >= ord })
+			if i < len(list) && list[i].Doc == ord {
+				list = append(list[:i], list[i+1:]...)
+			}
+			if len(list) == 0 {
+				delete(fieldPostings, term)
+			} else {
+				fieldPostings[term] = list
+			}
+		}
+	}
+
+	for _, field := range Fields {
+		ix.totals[field] -= ix.lengths[field][ord]
+		ix.lengths[field][ord] = 0
+	}
+	ix.docTerms[ord] = nil
+}
+
+// Len devuelve la cantidad de documentos vivos.
+func (ix *Index) Len() int {
+	return ix.live
+}
+
+// Ordinal devuelve el ordinal interno del documento con ese ID.
+func (ix *Index) Ordinal(id string) (int, bool) {
+	ord, ok := ix.ordinals[id]
+	return ord, ok
+}
+
+func (ix *Index) Postings(field Field, term string) []Posting {
+	return ix.postings[field][term]
+}
+
+func (ix *Index) docsWithAny(terms []string, fields []Field) []int {
+	var docs []int
+	for _, field := range fields {
+		for _, term := range terms {
+			for _, p := range ix.postings[field][term] {
+				docs = append(docs, p.Doc)
+			}
+		}
+	}
+
+	if len(fields) > 1 || len(terms) > 1 {
+		docs = sortUnique(docs)
+	}
+	return docs
+}
+
+func sortUnique(docs []int) []int {
+	sort.Ints(docs)
+	out := docs[:0]
+	for _, d := range docs {
+		if len(out) == 0 || d != out[len(out)-1] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func intersect(a, b []int) []int {
+	out := make([]int, 0, min(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			out = append(out, a[i])
+			i++
+			j++
+		}
+	}
+	return out
+}
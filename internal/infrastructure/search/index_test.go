@@ -0,0 +1,115 @@
+package search
+
+import (
+	"meli-product-api/internal/domain/analysis"
+	"slices"
+	"testing"
+)
+
+func doc(id, title string) Document {
+	return Document{ID: id, Fields: map[Field]string{FieldTitle: title}}
+}
+
+func TestIndexReusesOrdinals(t *testing.T) {
+	type op struct {
+		add    Document
+		remove string
+	}
+
+	tests := []struct {
+		name string
+		ops  []op
+		// want son los documentos que deben aparecer para cada término
+		want    map[string][]string
+		wantLen int
+		// wantSlots es la cantidad de ordinales asignados, vivos o libres
+		wantSlots int
+	}{
+		{
+			name: "update keeps the ordinal",
+			ops: []op{
+				{add: doc("a", "notebook lenovo")},
+				{add: doc("b", "notebook samsung")},
+				{add: doc("a", "celular lenovo")},
+				{add: doc("a", "celular samsung")},
+			},
+			want:      map[string][]string{"notebook": {"b"}, "lenovo": nil, "samsung": {"a", "b"}, "celular": {"a"}},
+			wantLen:   2,
+			wantSlots: 2,
+		},
+		{
+			name: "new document takes a removed ordinal",
+			ops: []op{
+				{add: doc("a", "notebook")},
+				{add: doc("b", "notebook")},
+				{add: doc("c", "notebook")},
+				{remove: "a"},
+				{add: doc("d", "notebook")},
+			},
+			want:      map[string][]string{"notebook": {"d", "b", "c"}},
+			wantLen:   3,
+			wantSlots: 3,
+		},
+		{
+			name: "removed document leaves no postings",
+			ops: []op{
+				{add: doc("a", "notebook")},
+				{remove: "a"},
+				{remove: "a"},
+			},
+			want:      map[string][]string{"notebook": nil},
+			wantSlots: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ix := NewIndex(analysis.NewAnalyzer())
+			for _, o := range tt.ops {
+				if o.add.ID != "" {
+					ix.Add(o.add)
+				} else {
+					ix.Remove(o.remove)
+				}
+			}
+
+			for term, want := range tt.want {
+				var got []string
+				list := ix.Postings(FieldTitle, ix.analyzer.Normalize(term))
+				for i, p := range list {
+					if i > 0 && list[i-1].Doc >= p.Doc {
+						t.Errorf("postings for %q are not sorted: %v", term, list)
+					}
+					got = append(got, ix.ids[p.Doc])
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("postings for %q = %v, want %v", term, got, want)
+				}
+			}
+			if ix.Len() != tt.wantLen {
+				t.Errorf("Len() = %d, want %d", ix.Len(), tt.wantLen)
+			}
+			if len(ix.ids) != tt.wantSlots {
+				t.Errorf("ordinals allocated = %d, want %d", len(ix.ids), tt.wantSlots)
+			}
+		})
+	}
+}
+
+func TestIndexKeepsFieldTotals(t *testing.T) {
+	ix := NewIndex(analysis.NewAnalyzer())
+	ix.Add(doc("a", "notebook lenovo thinkpad"))
+	ix.Add(doc("b", "celular"))
+
+	for range 100 {
+		ix.Add(doc("a", "notebook lenovo"))
+	}
+	ix.Remove("b")
+
+	if got := ix.totals[FieldTitle]; got != 2 {
+		t.Errorf("title total length = %d, want 2", got)
+	}
+	if ord, _ := ix.Ordinal("a"); ix.lengths[FieldTitle][ord] != 2 {
+		t.Errorf("title length of a = %d, want 2", ix.lengths[FieldTitle][ord])
+	}
+}
@@ -0,0 +1,110 @@
+package search
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Dictionary cuenta cuántas veces aparece cada palabra en el catálogo y
+// propone la corrección más probable para palabras desconocidas.
+//
+// Dictionary no es seguro para uso concurrente; el llamador debe sincronizar.
+type Dictionary struct {
+	freq map[string]int
+}
+
+func NewDictionary() *Dictionary {
+	return &Dictionary{freq: make(map[string]int)}
+}
+
+func (d *Dictionary) Add(words []string) {
+	for _, w := range words {
+		d.freq[w]++
+	}
+}
+
+func (d *Dictionary) Remove(words []string) {
+	for _, w := range words {
+		if d.freq[w] <= 1 {
+			delete(d.freq, w)
+		} else {
+			d.freq[w]--
+		}
+	}
+}
+
+func (d *Dictionary) Contains(word string) bool {
+	return d.freq[word] > 0
+}
+
+// Correct devuelve la palabra del diccionario más cercana a word: menor
+// distancia de edición y, a igual distancia, la más frecuente. Si ninguna
+// está dentro de MaxEdits, intenta separar word en dos palabras conocidas
+// ("smarttv" -> "smart tv").
+func (d *Dictionary) Correct(word string) ([]string, bool) {
+	if d.Contains(word) {
+		return []string{word}, false
+	}
+
+	if best, ok := d.closest(word); ok {
+		return []string{best}, true
+	}
+
+	if left, right, ok := d.split(word); ok {
+		return []string{left, right}, true
+	}
+
+	return []string{word}, false
+}
+
+func (d *Dictionary) closest(word string) (string, bool) {
+	maxEdits := MaxEdits(word)
+	if maxEdits == 0 {
+		return "", false
+	}
+
+	length := utf8.RuneCountInString(word)
+	best, bestDist, bestFreq := "", maxEdits+1, 0
+
+	// Sorted iteration keeps ties deterministic
+	candidates := make([]string, 0, len(d.freq))
+	for c := range d.freq {
+		if abs(utf8.RuneCountInString(c)-length) <= maxEdits {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Strings(candidates)
+
+	for _, c := range candidates {
+		dist := editDistance(word, c, maxEdits)
+		if dist > maxEdits {
+			continue
+		}
+		if dist < bestDist || (dist == bestDist && d.freq[c] > bestFreq) {
+			best, bestDist, bestFreq = c, dist, d.freq[c]
+		}
+	}
+
+	return best, best != ""
+}
+
+func (d *Dictionary) split(word string) (string, string, bool) {
+	best, bestFreq := -1, 0
+	for i := 2; i <= len(word)-2; i++ {
+		if !utf8.RuneStart(word[i]) {
+			continue
+		}
+
+		left, right := word[:i], word[i:]
+		if d.Contains(left) && d.Contains(right) {
+			if freq := min(d.freq[left], d.freq[right]); freq > bestFreq {
+				best, bestFreq = i, freq
+			}
+		}
+	}
+
+	if best < 0 {
+		return "", "", false
+	}
+	return word[:best], word[best:], true
+}
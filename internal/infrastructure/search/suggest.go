@@ -0,0 +1,261 @@
+package search
+
+import (
+	"meli-product-api/internal/domain/analysis"
+	"sort"
+	"strings"
+)
+
+// Completion es una sugerencia de autocompletado.
+type Completion struct {
+	Text   string
+	Kind   string
+	Weight int
+}
+
+type completionEntry struct {
+	Completion
+	norm string
+	refs int
+}
+
+type completionKey struct {
+	key   string
+	entry *completionEntry
+}
+
+// Suggester resuelve autocompletado por prefijo sobre listas ordenadas de
+// claves. Cada texto se indexa una vez por cada palabra en la que empieza,
+// así "max" completa tanto "Max..." como "iPhone 14 Pro Max". Las claves se
+// reparten en buckets por sus dos primeros bytes para que actualizar un
+// producto no implique mover la lista completa.
+//
+// Las claves agregadas quedan pendientes hasta llamar a Flush, que las
+// ordena e intercala de una sola vez; así la carga inicial no paga una
+// inserción ordenada por cada clave.
+//
+// Suggester no es seguro para uso concurrente; el llamador debe sincronizar.
+type Suggester struct {
+	analyzer *analysis.Analyzer
+	entries  map[string]*completionEntry
+	buckets  map[string][]completionKey
+	pending  []completionKey
+}
+
+func NewSuggester(analyzer *analysis.Analyzer) *Suggester {
+	return &Suggester{
+		analyzer: analyzer,
+		entries:  make(map[string]*completionEntry),
+		buckets:  make(map[string][]completionKey),
+	}
+}
+
+// Add suma weight al texto dado. Textos que normalizan igual se consideran
+// la misma sugerencia y conservan la primera forma vista.
+func (s *Suggester) Add(kind, text string, weight int) {
+	norm := s.normalize(text)
+	if norm == "" {
+		return
+	}
+
+	id := kind + "\x00" + norm
+	if e, ok := s.entries[id]; ok {
+		e.Weight += weight
+		e.refs++
+		return
+	}
+
+	e := &completionEntry{
+		Completion: Completion{Text: text, Kind: kind, Weight: weight},
+		norm:       norm,
+		refs:       1,
+	}
+	s.entries[id] = e
+
+	for _, key := range wordSuffixes(norm) {
+		s.pending = append(s.pending, completionKey{key: key, entry: e})
+	}
+}
+
+// Flush incorpora las claves pendientes. Debe llamarse antes de Suggest.
+func (s *Suggester) Flush() {
+	if len(s.pending) == 0 {
+		return
+	}
+
+	sort.Slice(s.pending, func(i, j int) bool { return s.pending[i].key < s.pending[j].key })
+
+	// pending is sorted, so keys of the same bucket are contiguous
+	for start := 0; start < len(s.pending); {
+		name := bucketName(s.pending[start].key)
+		end := start + 1
+		for end < len(s.pending) && bucketName(s.pending[end].key) == name {
+			end++
+		}
+		s.buckets[name] = mergeKeys(s.buckets[name], s.pending[start:end])
+		start = end
+	}
+
+	s.pending = nil
+}
+
+// Remove deshace un Add previo con los mismos argumentos.
+func (s *Suggester) Remove(kind, text string, weight int) {
+	norm := s.normalize(text)
+	id := kind + "\x00" + norm
+
+	e, ok := s.entries[id]
+	if !ok {
+		return
+	}
+
+	e.Weight -= weight
+	e.refs--
+	if e.refs > 0 {
+		return
+	}
+
+	s.Flush()
+	delete(s.entries, id)
+	for _, key := range wordSuffixes(norm) {
+		name := bucketName(key)
+		keys := s.buckets[name]
+		i := sort.Search(len(keys), func(i int) bool { return keys[i].key >= key })
+		for ; i < len(keys) && keys[i].key == key; i++ {
+			if keys[i].entry == e {
+				keys = append(keys[:i], keys[i+1:]...)
+				break
+			}
+		}
+
+		if len(keys) == 0 {
+			delete(s.buckets, name)
+		} else {
+			s.buckets[name] = keys
+		}
+	}
+}
+
+// Suggest devuelve hasta limit sugerencias cuyo texto tiene alguna palabra
+// que empieza con prefix, ordenadas por peso descendente.
+func (s *Suggester) Suggest(prefix string, limit int) []Completion {
+	norm := s.normalize(prefix)
+	if norm == "" || limit <= 0 {
+		return nil
+	}
+
+	top := &topCompletions{limit: limit}
+
+	if len(norm) >= 2 {
+		top.scan(s.buckets[bucketName(norm)], norm)
+	} else {
+		// A single byte spans every bucket that starts with it
+		for name, keys := range s.buckets {
+			if name[0] == norm[0] {
+				top.scan(keys, norm)
+			}
+		}
+	}
+
+	return top.sorted()
+}
+
+// normalize pliega tildes y mayúsculas y colapsa la puntuación a espacios
+// simples, sin stemming: el usuario todavía está escribiendo la palabra.
+func (s *Suggester) normalize(text string) string {
+	tokens := s.analyzer.Tokenize(text)
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.Term
+	}
+	return strings.Join(words, " ")
+}
+
+// topCompletions conserva las limit mejores entradas vistas. Una entrada
+// puede aparecer varias veces (una por palabra); basta con compararla
+// contra las que ya están adentro, porque si fue descartada antes tampoco
+// califica ahora.
+type topCompletions struct {
+	limit   int
+	entries []*completionEntry
+}
+
+func (t *topCompletions) scan(keys []completionKey, prefix string) {
+	i := sort.Search(len(keys), func(i int) bool { return keys[i].key >= prefix })
+	for ; i < len(keys) && strings.HasPrefix(keys[i].key, prefix); i++ {
+		t.offer(keys[i].entry)
+	}
+}
+
+func (t *topCompletions) offer(e *completionEntry) {
+	if len(t.entries) == t.limit && !better(e, t.entries[len(t.entries)-1]) {
+		return
+	}
+	for _, existing := range t.entries {
+		if existing == e {
+			return
+		}
+	}
+
+	i := sort.Search(len(t.entries), func(i int) bool { return better(e, t.entries[i]) })
+	t.entries = append(t.entries, nil)
+	copy(t.entries[i+1:], t.entries[i:])
+	t.entries[i] = e
+
+	if len(t.entries) > t.limit {
+		t.entries = t.entries[:t.limit]
+	}
+}
+
+func (t *topCompletions) sorted() []Completion {
+	out := make([]Completion, len(t.entries))
+	for i, e := range t.entries {
+		out[i] = e.Completion
+	}
+	return out
+}
+
+// better ordena por peso descendente y, a igual peso, prefiere textos
+// más cortos.
+func better(a, b *completionEntry) bool {
+	if a.Weight != b.Weight {
+		return a.Weight > b.Weight
+	}
+	if len(a.norm) != len(b.norm) {
+		return len(a.norm) < len(b.norm)
+	}
+	return a.norm < b.norm
+}
+
+func bucketName(key string) string {
+	if len(key) < 2 {
+		return key
+	}
+	return key[:2]
+}
+
+func mergeKeys(keys, sorted []completionKey) []completionKey {
+	merged := make([]completionKey, 0, len(keys)+len(sorted))
+	i, j := 0, 0
+	for i < len(keys) && j < len(sorted) {
+		if keys[i].key <= sorted[j].key {
+			merged = append(merged, keys[i])
+			i++
+		} else {
+			merged = append(merged, sorted[j])
+			j++
+		}
+	}
+	merged = append(merged, keys[i:]...)
+	return append(merged, sorted[j:]...)
+}
+
+func wordSuffixes(norm string) []string {
+	suffixes := []string{norm}
+	for i := 0; i < len(norm); i++ {
+		if norm[i] == ' ' {
+			suffixes = append(suffixes, norm[i+1:])
+		}
+	}
+	return suffixes
+}